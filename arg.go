@@ -1,53 +1,160 @@
 package cobraargs
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const DefaultValueOnListSeparator = ":"
 
 type Argument struct {
-	Required        bool
-	LongName        string
-	ShortName       string
-	HasDefaultValue bool
-	DefaultValue    string
-	OnListSeparator string
+	Required            bool
+	LongName            string
+	ShortName           string
+	HasDefaultValue     bool
+	DefaultValue        string
+	OnListSeparator     string
+	Layout              string
+	Schemes             []string
+	Type                string
+	Encoding            string
+	OneOf               []string
+	ChoiceHelp          []string
+	Format              string
+	Mode                string
+	Hidden              bool
+	Deprecated          string
+	ShorthandDeprecated string
+	Aliases             []string
+	HasNoOptDefault     bool
+	NoOptDefault        string
+	Placeholder         string
+	Persistent          bool
+	Negatable           bool
+	Env                 []string
+	Config              string
+	Secret              bool
+	Indirect            []string
+	ExpandEnv           bool
+	FromDir             string
+	RequiredIf          string
+	RequiredUnless      string
+	Xor                 []string
+	Together            []string
+	OneRequired         []string
+	Min                 string
+	Max                 string
+	MinLen              int
+	MaxLen              int
+	Pattern             string
+	Exists              string
+	Validate            []string
+	Requires            []string
+	Conflicts           []string
+	Positional          bool
+	HasPosition         bool
+	Position            int
+	Rest                bool
+	Passthrough         bool
+	HasPrefix           bool
+	Prefix              string
+	Complete            string
+	UnknownTagKeys      []string
 }
 
+// ParseArgFromField reads the 'arg' tag of field into an Argument.
 func ParseArgFromField(field reflect.StructField) (argument Argument, err error) {
+	return parseArgFromField(field, Options{})
+}
+
+func parseArgFromField(field reflect.StructField, options Options) (argument Argument, err error) {
+	rawArgStr, _ := options.argTag(field)
+	return parseArgTag(field, rawArgStr, options.NameStyle)
+}
+
+// parseArgTag reads rawArgStr, the 'arg' tag of field or that tag with overrides appended, into an Argument whose default long name is in style.
+func parseArgTag(field reflect.StructField, rawArgStr string, style NameStyle) (argument Argument, err error) {
 	if len(field.Name) < 2 {
-		return argument, fmt.Errorf("arg item field [%v] has a name that is less than 2, this is illegal", field.Name)
+		return argument, newArgError(ErrInvalidFieldName, nil, field.Name, "", "", "arg item field [%v] has a name that is less than 2, this is illegal", field.Name)
 	}
 
-	defaultName := strings.ToLower(field.Name[0:1]) + field.Name[1:]
-	argument.LongName = defaultName
-	rawArgStr := field.Tag.Get("arg")
-	argItems := strings.Split(rawArgStr, ",")
-	for index, argItem := range argItems {
-		nameValue := strings.Split(argItem, "=")
-		if len(nameValue) != 2 {
-			return argument, fmt.Errorf("arg item at %v index for field '%v' is not a single '='", index, field.Name)
+	argument.LongName = longNameOf(field.Name, style)
+	if rawArgStr == "" {
+		return argument, nil
+	}
+	scanner := tagScanner{raw: rawArgStr}
+	inValidate := false
+	for index := 0; ; index++ {
+		argItem, ok, err := scanner.next()
+		if err != nil {
+			return argument, newArgError(ErrInvalidTagSyntax, nil, field.Name, "", rawArgStr, "arg tag for field '%v' has %v: [%v]", field.Name, err, rawArgStr)
+		}
+		if !ok {
+			break
+		}
+		tagName := strings.ToLower(argItem.Name)
+		tagValue := argItem.Value
+		if inValidate && isValidatorListItem(argItem, tagName) {
+			argument.Validate = append(argument.Validate, tagName)
+			continue
+		}
+		inValidate = tagName == "validate"
+		if !argItem.HasValue {
+			if !isBoolTagKey(tagName) && (tagName == "" || containsString(knownTagKeys, tagName)) {
+				return argument, newArgError(ErrInvalidTagSyntax, nil, field.Name, tagName, argItem.Raw, "arg item at %v index for field '%v' has no '='", index, field.Name)
+			}
+			tagValue = "true"
 		}
-		tagName := strings.ToLower(nameValue[0])
-		tagValue := nameValue[1]
 		err = processArg(&argument, field.Name, tagName, tagValue)
 		if err != nil {
 			return argument, err
 		}
 	}
+	if argument.ShorthandDeprecated != "" && argument.ShortName == "" {
+		return argument, newArgError(ErrInvalidTagValue, nil, field.Name, "shorthanddeprecated", argument.ShorthandDeprecated, "arg field %v has 'shorthanddeprecated' but no 'shortname'", field.Name)
+	}
+	if len(argument.ChoiceHelp) > 0 && len(argument.ChoiceHelp) != len(argument.OneOf) {
+		return argument, newArgError(ErrInvalidTagValue, nil, field.Name, "choicehelp", strings.Join(argument.ChoiceHelp, "|"), "arg field %v has %v 'choicehelp' descriptions for %v 'oneof' choices", field.Name, len(argument.ChoiceHelp), len(argument.OneOf))
+	}
+	if argument.HasDefaultValue && (argument.ExpandEnv || ExpandEnvDefaults()) {
+		argument.DefaultValue = os.ExpandEnv(argument.DefaultValue)
+	}
+	if len(argument.UnknownTagKeys) > 0 && StrictTagKeys() {
+		return argument, unknownTagKeyError(nil, field.Name, argument.UnknownTagKeys[0])
+	}
 	return argument, nil
 }
 
+var expandEnvDefaults atomic.Bool
+
+// SetExpandEnvDefaults turns on or off, for the whole process, expanding ${VAR} and $VAR in every tag default value with os.ExpandEnv, as the 'expandenv' tag key does for one field.
+func SetExpandEnvDefaults(expand bool) {
+	expandEnvDefaults.Store(expand)
+}
+
+// ExpandEnvDefaults reports whether every tag default value is expanded.
+func ExpandEnvDefaults() bool {
+	return expandEnvDefaults.Load()
+}
+
 func processArgRequired(argument *Argument, fieldName, tagName, tagValue string) error {
 	required, err := strconv.ParseBool(tagValue)
 	if err != nil {
-		return fmt.Errorf("arg field %v for 'required' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'required' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
 	}
 	argument.Required = required
 	return nil
@@ -66,7 +173,7 @@ func processArgDefaultValue(argument *Argument, tagValue string) {
 
 func processArgShortName(argument *Argument, fieldName, tagName, tagValue string) error {
 	if len(tagValue) > 1 {
-		return fmt.Errorf("arg field %v for 'shortname' field's value is greater than 1 character, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+		return newArgError(ErrShortNameTooLong, nil, fieldName, tagName, tagValue, "arg field %v for 'shortname' field's value is greater than 1 character, it's name/value %v/[%v]", fieldName, tagName, tagValue)
 	}
 	argument.ShortName = strings.ToLower(tagValue)
 	return nil
@@ -74,12 +181,215 @@ func processArgShortName(argument *Argument, fieldName, tagName, tagValue string
 
 func processOnListSeparator(argument *Argument, fieldName, tagName, tagValue string) error {
 	if len(tagValue) > 1 {
-		return fmt.Errorf("arg field %v for 'onlistseperator' field's value is greater than 1 character, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'onlistseperator' field's value is greater than 1 character, it's name/value %v/[%v]", fieldName, tagName, tagValue)
 	}
 	argument.OnListSeparator = tagValue
 	return nil
 }
 
+func processArgEncoding(argument *Argument, fieldName, tagName, tagValue string) error {
+	encoding := strings.ToLower(tagValue)
+	if encoding != "hex" && encoding != "base64" {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'encoding' field is not hex or base64, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Encoding = encoding
+	return nil
+}
+
+func processArgMode(argument *Argument, fieldName, tagName, tagValue string) error {
+	mode := strings.ToLower(tagValue)
+	if mode != "array" && mode != "slice" {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'mode' field is not array or slice, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Mode = mode
+	return nil
+}
+
+func processArgHidden(argument *Argument, fieldName, tagName, tagValue string) error {
+	hidden, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'hidden' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Hidden = hidden
+	return nil
+}
+
+func processArgPersistent(argument *Argument, fieldName, tagName, tagValue string) error {
+	persistent, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'persistent' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Persistent = persistent
+	return nil
+}
+
+func processArgNegatable(argument *Argument, fieldName, tagName, tagValue string) error {
+	negatable, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'negatable' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Negatable = negatable
+	return nil
+}
+
+func processArgSecret(argument *Argument, fieldName, tagName, tagValue string) error {
+	secret, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'secret' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Secret = secret
+	return nil
+}
+
+func processArgExpandEnv(argument *Argument, fieldName, tagName, tagValue string) error {
+	expandEnv, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'expandenv' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.ExpandEnv = expandEnv
+	return nil
+}
+
+func processArgCondition(argument *Argument, fieldName, tagName, tagValue string) error {
+	name, values, ok := strings.Cut(tagValue, "=")
+	if !ok || name == "" || values == "" {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for '%v' field is not of the form flag=value, it's [%v]", fieldName, tagName, tagValue)
+	}
+	if tagName == "requiredif" {
+		argument.RequiredIf = tagValue
+	} else {
+		argument.RequiredUnless = tagValue
+	}
+	return nil
+}
+
+func processArgBound(bound *string, fieldName, tagName, tagValue string) error {
+	if _, ok := new(big.Rat).SetString(tagValue); !ok {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for '%v' field is not a number, it's [%v]", fieldName, tagName, tagValue)
+	}
+	*bound = tagValue
+	return nil
+}
+
+func processArgLength(length *int, fieldName, tagName, tagValue string) error {
+	value, err := strconv.Atoi(tagValue)
+	if err != nil || value < 1 {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for '%v' field is not a positive integer, it's [%v]", fieldName, tagName, tagValue)
+	}
+	*length = value
+	return nil
+}
+
+func processArgExists(argument *Argument, fieldName, tagName, tagValue string) error {
+	exists := strings.ToLower(tagValue)
+	if exists != "file" && exists != "dir" {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'exists' field is not file or dir, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Exists = exists
+	return nil
+}
+
+// isValidatorListItem reports whether a tag item following a 'validate' item continues its list of validators, as hostname does in validate=port,hostname: a bare item naming a registered validator rather than a tag key.
+func isValidatorListItem(argItem tagItem, tagName string) bool {
+	if argItem.HasValue || isBoolTagKey(tagName) || containsString(knownTagKeys, tagName) {
+		return false
+	}
+	_, registered := lookupValidator(tagName)
+	return registered
+}
+
+// processArgValidate accepts validator names separated by '|', or by ',' when the value is quoted as in validate='port,hostname'. Unquoted, as in validate=port,hostname, parseArgTag takes the bare items naming registered validators after it.
+func processArgValidate(argument *Argument, fieldName, tagName, tagValue string) error {
+	argument.Validate = strings.FieldsFunc(strings.ToLower(tagValue), func(r rune) bool { return r == '|' || r == ',' })
+	for _, name := range argument.Validate {
+		if _, registered := lookupValidator(name); !registered {
+			return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'validate' field has validator [%v] which is not registered", fieldName, name)
+		}
+	}
+	return nil
+}
+
+func processArgPosition(argument *Argument, fieldName, tagName, tagValue string) error {
+	if strings.ToLower(tagValue) == "rest" {
+		argument.Positional = true
+		argument.Rest = true
+		return nil
+	}
+	position, err := strconv.Atoi(tagValue)
+	if err != nil || position < 0 {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'pos' field is not a position of 0 or more, it's [%v]", fieldName, tagValue)
+	}
+	argument.Positional = true
+	argument.HasPosition = true
+	argument.Position = position
+	return nil
+}
+
+func processArgPositional(argument *Argument, fieldName, tagName, tagValue string) error {
+	positional, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'positional' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Positional = positional || argument.HasPosition
+	return nil
+}
+
+func processArgPassthrough(argument *Argument, fieldName, tagName, tagValue string) error {
+	passthrough, err := strconv.ParseBool(tagValue)
+	if err != nil {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'passthrough' field is not a boolean, it's name/value %v/[%v]", fieldName, tagName, tagValue)
+	}
+	argument.Passthrough = passthrough
+	argument.Positional = passthrough || argument.Positional
+	return nil
+}
+
+func processArgIndirect(argument *Argument, fieldName, tagName, tagValue string) error {
+	switch strings.ToLower(tagValue) {
+	case "true":
+		argument.Indirect = []string{"file", "env"}
+		return nil
+	case "false":
+		argument.Indirect = nil
+		return nil
+	}
+	argument.Indirect = strings.Split(strings.ToLower(tagValue), "|")
+	for _, scheme := range argument.Indirect {
+		if _, registered := lookupResolver(scheme); !registered && !containsString(indirectSchemes, scheme) {
+			return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'indirect' field has scheme [%v] which is not one of %v or a registered resolver", fieldName, scheme, strings.Join(indirectSchemes, "|"))
+		}
+	}
+	return nil
+}
+
+func processArgDeprecated(message *string, fieldName, tagName, tagValue string) error {
+	if tagValue == "" {
+		return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for '%v' field needs a deprecation message", fieldName, tagName)
+	}
+	*message = tagValue
+	return nil
+}
+
+// knownTagKeys lists every key processArg understands, for 'did you mean' suggestions.
+var knownTagKeys = []string{
+	"required", "longname", "defaultvalue", "shortname", "onlistseparator", "sep", "layout",
+	"type", "encoding", "mode", "format", "oneof", "schemes", "hidden", "deprecated", "shorthanddeprecated",
+	"aliases", "nooptdefault", "placeholder", "persistent",
+	"negatable", "env", "config", "secret", "indirect",
+	"expandenv", "fromdir", "requiredif", "requiredunless",
+	"xor", "together", "onerequired", "min", "max",
+	"minlen", "maxlen", "pattern", "exists",
+	"validate", "requires", "conflicts", "pos", "positional",
+	"passthrough", "prefix", "complete", "choicehelp",
+}
+
+// boolTagKeys lists the keys that may be written bare, e.g. `arg:"required"` for `arg:"required=true"`.
+var boolTagKeys = []string{"required", "hidden", "persistent", "negatable", "secret", "indirect", "expandenv", "positional", "passthrough"}
+
+func isBoolTagKey(tagName string) bool {
+	return containsString(boolTagKeys, tagName)
+}
+
 func processArg(argument *Argument, fieldName, tagName, tagValue string) error {
 	tagName = strings.ToLower(tagName)
 	switch tagName {
@@ -93,48 +403,206 @@ func processArg(argument *Argument, fieldName, tagName, tagValue string) error {
 		return nil
 	case "shortname":
 		return processArgShortName(argument, fieldName, tagName, tagValue)
-	case "onlistseparator":
+	case "onlistseparator", "sep":
 		return processOnListSeparator(argument, fieldName, tagName, tagValue)
+	case "layout":
+		argument.Layout = tagValue
+		return nil
+	case "type":
+		argument.Type = strings.ToLower(tagValue)
+		return nil
+	case "encoding":
+		return processArgEncoding(argument, fieldName, tagName, tagValue)
+	case "mode":
+		return processArgMode(argument, fieldName, tagName, tagValue)
+	case "format":
+		argument.Format = strings.ToLower(tagValue)
+		return nil
+	case "oneof":
+		argument.OneOf = strings.Split(tagValue, "|")
+		return nil
+	case "choicehelp":
+		argument.ChoiceHelp = strings.Split(tagValue, "|")
+		return nil
+	case "schemes":
+		argument.Schemes = strings.Split(strings.ToLower(tagValue), "|")
+		return nil
+	case "hidden":
+		return processArgHidden(argument, fieldName, tagName, tagValue)
+	case "deprecated":
+		return processArgDeprecated(&argument.Deprecated, fieldName, tagName, tagValue)
+	case "shorthanddeprecated":
+		return processArgDeprecated(&argument.ShorthandDeprecated, fieldName, tagName, tagValue)
+	case "aliases":
+		argument.Aliases = strings.Split(tagValue, "|")
+		return nil
+	case "nooptdefault":
+		argument.NoOptDefault = tagValue
+		argument.HasNoOptDefault = true
+		return nil
+	case "placeholder":
+		argument.Placeholder = tagValue
+		return nil
+	case "persistent":
+		return processArgPersistent(argument, fieldName, tagName, tagValue)
+	case "negatable":
+		return processArgNegatable(argument, fieldName, tagName, tagValue)
+	case "env":
+		argument.Env = strings.Split(tagValue, "|")
+		return nil
+	case "config":
+		argument.Config = tagValue
+		return nil
+	case "secret":
+		return processArgSecret(argument, fieldName, tagName, tagValue)
+	case "indirect":
+		return processArgIndirect(argument, fieldName, tagName, tagValue)
+	case "requiredif", "requiredunless":
+		return processArgCondition(argument, fieldName, tagName, tagValue)
+	case "min":
+		return processArgBound(&argument.Min, fieldName, tagName, tagValue)
+	case "max":
+		return processArgBound(&argument.Max, fieldName, tagName, tagValue)
+	case "minlen":
+		return processArgLength(&argument.MinLen, fieldName, tagName, tagValue)
+	case "maxlen":
+		return processArgLength(&argument.MaxLen, fieldName, tagName, tagValue)
+	case "pattern":
+		if _, err := regexp.Compile(tagValue); err != nil {
+			return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'pattern' field is not a valid regular expression: %v", fieldName, err)
+		}
+		argument.Pattern = tagValue
+		return nil
+	case "exists":
+		return processArgExists(argument, fieldName, tagName, tagValue)
+	case "validate":
+		return processArgValidate(argument, fieldName, tagName, tagValue)
+	case "requires":
+		argument.Requires = strings.Split(tagValue, "|")
+		return nil
+	case "conflicts":
+		argument.Conflicts = strings.Split(tagValue, "|")
+		return nil
+	case "pos":
+		return processArgPosition(argument, fieldName, tagName, tagValue)
+	case "positional":
+		return processArgPositional(argument, fieldName, tagName, tagValue)
+	case "passthrough":
+		return processArgPassthrough(argument, fieldName, tagName, tagValue)
+	case "complete":
+		if _, registered := lookupCompleter(tagValue); !registered && !isBuiltinCompletion(tagValue) {
+			return newArgError(ErrInvalidTagValue, nil, fieldName, tagName, tagValue, "arg field %v for 'complete' field is not files, dirs, none, exec:<command>, http:<method> <path> or a registered completer, it's [%v]", fieldName, tagValue)
+		}
+		argument.Complete = tagValue
+		return nil
+	case "prefix":
+		argument.Prefix = tagValue
+		argument.HasPrefix = true
+		return nil
+	case "xor":
+		argument.Xor = strings.Split(tagValue, "|")
+		return nil
+	case "together":
+		argument.Together = strings.Split(tagValue, "|")
+		return nil
+	case "onerequired":
+		argument.OneRequired = strings.Split(tagValue, "|")
+		return nil
+	case "fromdir":
+		argument.FromDir = tagValue
+		return nil
+	case "expandenv":
+		return processArgExpandEnv(argument, fieldName, tagName, tagValue)
 	}
 
+	argument.UnknownTagKeys = append(argument.UnknownTagKeys, tagName)
 	return nil
 }
 
 // AttachStringListArg uses reflection to read the provided struct to determine the arguments.
 func AttachStringListArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) {
-	arg, rawHelp := parseArg(parmType, variableName)
-	var defaultValue []string
-	if arg.HasDefaultValue {
-		seperator := arg.OnListSeparator
-		if seperator == "" {
-			seperator = DefaultValueOnListSeparator
-		}
-		defaultValue = strings.Split(arg.DefaultValue, seperator)
+	mustAttach(AttachStringListArgE(cmd, parmType, variableName, variableValue, defaultValues...))
+}
+
+// AttachStringListArgE is AttachStringListArg returning an error instead of panicking.
+func AttachStringListArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
 	}
+	defaultValue := splitDefaultValue(arg)
 	if len(defaultValues) > 0 {
 		defaultValue = defaultValues
 	}
 	//p *[]string, name, shorthand string, value []string, usage string
-	cmd.Flags().StringArrayVarP(variableValue, arg.LongName, arg.ShortName, defaultValue, rationalizeHelp(arg, rawHelp))
-	processRequiredArg(cmd, arg)
+	argFlags(cmd, arg).StringArrayVarP(variableValue, arg.LongName, arg.ShortName, defaultValue, rationalizeHelp(arg, rawHelp))
+	return configureFlagArg(cmd, arg)
+}
+
+// AttachStringSliceArg uses reflection to read the provided struct to determine the arguments. Unlike AttachStringListArg, values given on the command line are split on commas. The tag default value is split on the 'sep' (or 'onlistseparator') tag value.
+func AttachStringSliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) {
+	mustAttach(AttachStringSliceArgE(cmd, parmType, variableName, variableValue, defaultValues...))
+}
+
+// AttachStringSliceArgE is AttachStringSliceArg returning an error instead of panicking.
+func AttachStringSliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
+	}
+	defaultValue := splitDefaultValue(arg)
+	if len(defaultValues) > 0 {
+		defaultValue = defaultValues
+	}
+	argFlags(cmd, arg).StringSliceVarP(variableValue, arg.LongName, arg.ShortName, defaultValue, rationalizeHelp(arg, rawHelp))
+	return configureFlagArg(cmd, arg)
+}
+
+// AttachStringArrayArg uses reflection to read the provided struct to determine the arguments. Each repeated flag value is kept verbatim, commas included. This is the default for []string struct fields; 'mode=slice' selects comma splitting instead.
+func AttachStringArrayArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) {
+	AttachStringListArg(cmd, parmType, variableName, variableValue, defaultValues...)
+}
+
+// AttachStringArrayArgE is AttachStringArrayArg returning an error instead of panicking.
+func AttachStringArrayArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]string, defaultValues ...string) error {
+	return AttachStringListArgE(cmd, parmType, variableName, variableValue, defaultValues...)
+}
+
+func splitDefaultValue(arg Argument) []string {
+	if !arg.HasDefaultValue {
+		return nil
+	}
+	seperator := arg.OnListSeparator
+	if seperator == "" {
+		seperator = DefaultValueOnListSeparator
+	}
+	return strings.Split(arg.DefaultValue, seperator)
 }
 
 // AttachStringArg uses reflection to read the provided struct to determine the arguments. otherArgs has the first argument is the defaultDefault value that overrides anything defined in the struct argument tag.
 func AttachStringArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *string, otherArgs ...string) {
-	arg, rawHelp := parseArg(parmType, variableName)
-	var defaultValue string
-	if arg.HasDefaultValue {
-		defaultValue = arg.DefaultValue
+	mustAttach(AttachStringArgE(cmd, parmType, variableName, variableValue, otherArgs...))
+}
+
+// AttachStringArgE is AttachStringArg returning an error instead of panicking.
+func AttachStringArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *string, otherArgs ...string) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
 	}
 	if len(otherArgs) > 0 {
-		defaultValue = otherArgs[0]
+		arg.DefaultValue = otherArgs[0]
+		arg.HasDefaultValue = true
 	}
-	cmd.Flags().StringVarP(variableValue, arg.LongName, arg.ShortName, defaultValue, rationalizeHelp(arg, rawHelp))
-	processRequiredArg(cmd, arg)
+	return attachSingleFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
 }
 
 type genericStringToValueConverter func(string) (interface{}, error)
 
+func stringStringToValueConverter(val string) (interface{}, error) {
+	return val, nil
+}
+
 func booleanStringToValueConverter(val string) (interface{}, error) {
 	return strconv.ParseBool(val)
 }
@@ -143,33 +611,465 @@ func intStringToValueConverter(val string) (interface{}, error) {
 	return strconv.Atoi(val)
 }
 
-func attachCommonArg(arg Argument, parmType reflect.Type, variableName string, converter genericStringToValueConverter) (defaultValue interface{}) {
-	var err error
-	if arg.HasDefaultValue {
-		defaultValue, err = converter(arg.DefaultValue)
+func int8StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseInt(val, 10, 8)
+	return int8(value), err
+}
+
+func int16StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseInt(val, 10, 16)
+	return int16(value), err
+}
+
+func int32StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseInt(val, 10, 32)
+	return int32(value), err
+}
+
+func int64StringToValueConverter(val string) (interface{}, error) {
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func uintStringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseUint(val, 10, 0)
+	return uint(value), err
+}
+
+func uint8StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseUint(val, 10, 8)
+	return uint8(value), err
+}
+
+func uint16StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseUint(val, 10, 16)
+	return uint16(value), err
+}
+
+func uint32StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseUint(val, 10, 32)
+	return uint32(value), err
+}
+
+func uint64StringToValueConverter(val string) (interface{}, error) {
+	return strconv.ParseUint(val, 10, 64)
+}
+
+func float32StringToValueConverter(val string) (interface{}, error) {
+	value, err := strconv.ParseFloat(val, 32)
+	return float32(value), err
+}
+
+func float64StringToValueConverter(val string) (interface{}, error) {
+	return strconv.ParseFloat(val, 64)
+}
+
+func ipStringToValueConverter(val string) (interface{}, error) {
+	ip := net.ParseIP(strings.TrimSpace(val))
+	if ip == nil {
+		return nil, fmt.Errorf("value [%v] is not an IP address", val)
+	}
+	return ip, nil
+}
+
+func ipNetStringToValueConverter(val string) (interface{}, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(val))
+	if err != nil {
+		return nil, err
+	}
+	return *ipNet, nil
+}
+
+func bytesHexStringToValueConverter(val string) (interface{}, error) {
+	return hex.DecodeString(strings.TrimSpace(val))
+}
+
+func bytesBase64StringToValueConverter(val string) (interface{}, error) {
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(val))
+}
+
+func durationStringToValueConverter(val string) (interface{}, error) {
+	return time.ParseDuration(val)
+}
+
+func convertDefaultValue(arg Argument, parmType reflect.Type, variableName string, converter genericStringToValueConverter, zeroValue interface{}) (defaultValue interface{}, err error) {
+	if !arg.HasDefaultValue {
+		return zeroValue, nil
+	}
+	defaultValue, err = converter(arg.DefaultValue)
+	if err != nil {
+		return nil, badDefaultValueError(parmType, variableName, arg)
+	}
+	return defaultValue, nil
+}
+
+// convertDefaultValues splits the default value on the list separator and converts each item.
+func convertDefaultValues(arg Argument, parmType reflect.Type, variableName string, converter genericStringToValueConverter) (defaultValues []interface{}, err error) {
+	for _, item := range splitDefaultValue(arg) {
+		defaultValue, err := converter(item)
 		if err != nil {
-			msg := fmt.Sprintf("Fatal mis-configuration. Field %v.%v could not process default value: %v", parmType.Name(), variableName, arg.DefaultValue)
-			panic(msg)
+			return nil, newArgError(ErrBadDefaultValue, parmType, variableName, "defaultvalue", arg.DefaultValue, "field %v.%v could not process default value item [%v] of %v", parmType.Name(), variableName, item, arg.DefaultValue)
 		}
-		return defaultValue
+		defaultValues = append(defaultValues, defaultValue)
 	}
-	return nil
+	return defaultValues, nil
 }
 
+// convertDefaultMap splits the default value on the list separator and converts each 'key=value' item.
+func convertDefaultMap(arg Argument, parmType reflect.Type, variableName string, converter genericStringToValueConverter) (defaultValues map[string]interface{}, err error) {
+	defaultValues = map[string]interface{}{}
+	for _, item := range splitDefaultValue(arg) {
+		keyValue := strings.SplitN(item, "=", 2)
+		if len(keyValue) != 2 {
+			return nil, newArgError(ErrBadDefaultValue, parmType, variableName, "defaultvalue", arg.DefaultValue, "field %v.%v default value item [%v] of %v is not a key=value pair", parmType.Name(), variableName, item, arg.DefaultValue)
+		}
+		defaultValue, err := converter(keyValue[1])
+		if err != nil {
+			return nil, newArgError(ErrBadDefaultValue, parmType, variableName, "defaultvalue", arg.DefaultValue, "field %v.%v could not process default value item [%v] of %v", parmType.Name(), variableName, item, arg.DefaultValue)
+		}
+		defaultValues[keyValue[0]] = defaultValue
+	}
+	return defaultValues, nil
+}
+
+// AttachBoolArg uses reflection to read the provided struct to determine the arguments.
 func AttachBoolArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *bool) {
-	arg, rawHelp := parseArg(parmType, variableName)
-	defaultValue := attachCommonArg(arg, parmType, variableName, booleanStringToValueConverter)
-	defaultValueBool := defaultValue.(bool) // Note: type conversion should not alter from default value if it's invalid
-	cmd.Flags().BoolVarP(variableValue, arg.LongName, arg.ShortName, defaultValueBool, rationalizeHelp(arg, rawHelp))
-	processRequiredArg(cmd, arg)
+	mustAttach(AttachBoolArgE(cmd, parmType, variableName, variableValue))
 }
 
+// AttachBoolArgE is AttachBoolArg returning an error instead of panicking.
+func AttachBoolArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *bool) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachIntArg uses reflection to read the provided struct to determine the arguments.
 func AttachIntArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int) {
-	arg, rawHelp := parseArg(parmType, variableName)
-	defaultValue := attachCommonArg(arg, parmType, variableName, intStringToValueConverter)
-	defaultValueInt := defaultValue.(int)
-	cmd.Flags().IntVarP(variableValue, arg.LongName, arg.ShortName, defaultValueInt, rationalizeHelp(arg, rawHelp))
-	processRequiredArg(cmd, arg)
+	mustAttach(AttachIntArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachIntArgE is AttachIntArg returning an error instead of panicking.
+func AttachIntArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachFloat32Arg uses reflection to read the provided struct to determine the arguments.
+func AttachFloat32Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *float32) {
+	mustAttach(AttachFloat32ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachFloat32ArgE is AttachFloat32Arg returning an error instead of panicking.
+func AttachFloat32ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *float32) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachFloat64Arg uses reflection to read the provided struct to determine the arguments.
+func AttachFloat64Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *float64) {
+	mustAttach(AttachFloat64ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachFloat64ArgE is AttachFloat64Arg returning an error instead of panicking.
+func AttachFloat64ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *float64) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachDurationArg uses reflection to read the provided struct to determine the arguments. Default values use time.ParseDuration syntax such as '30s'.
+func AttachDurationArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *time.Duration) {
+	mustAttach(AttachDurationArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachDurationArgE is AttachDurationArg returning an error instead of panicking.
+func AttachDurationArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *time.Duration) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachIntSliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachIntSliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]int) {
+	mustAttach(AttachIntSliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachIntSliceArgE is AttachIntSliceArg returning an error instead of panicking.
+func AttachIntSliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]int) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachInt64SliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachInt64SliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]int64) {
+	mustAttach(AttachInt64SliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachInt64SliceArgE is AttachInt64SliceArg returning an error instead of panicking.
+func AttachInt64SliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]int64) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachFloat32SliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachFloat32SliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]float32) {
+	mustAttach(AttachFloat32SliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachFloat32SliceArgE is AttachFloat32SliceArg returning an error instead of panicking.
+func AttachFloat32SliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]float32) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachFloat64SliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachFloat64SliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]float64) {
+	mustAttach(AttachFloat64SliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachFloat64SliceArgE is AttachFloat64SliceArg returning an error instead of panicking.
+func AttachFloat64SliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]float64) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachDurationSliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachDurationSliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]time.Duration) {
+	mustAttach(AttachDurationSliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachDurationSliceArgE is AttachDurationSliceArg returning an error instead of panicking.
+func AttachDurationSliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]time.Duration) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachIPSliceArg uses reflection to read the provided struct to determine the arguments. The flag may be repeated or given comma separated values.
+func AttachIPSliceArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]net.IP) {
+	mustAttach(AttachIPSliceArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachIPSliceArgE is AttachIPSliceArg returning an error instead of panicking.
+func AttachIPSliceArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]net.IP) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachStringToStringArg uses reflection to read the provided struct to determine the arguments. The flag collects repeated key=value pairs; the tag default value holds pairs such as 'env=prod:tier=web'.
+func AttachStringToStringArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *map[string]string) {
+	mustAttach(AttachStringToStringArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachStringToStringArgE is AttachStringToStringArg returning an error instead of panicking.
+func AttachStringToStringArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *map[string]string) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachStringToIntArg uses reflection to read the provided struct to determine the arguments. The flag collects repeated key=value pairs with integer values.
+func AttachStringToIntArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *map[string]int) {
+	mustAttach(AttachStringToIntArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachStringToIntArgE is AttachStringToIntArg returning an error instead of panicking.
+func AttachStringToIntArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *map[string]int) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachBytesArg uses reflection to read the provided struct to determine the arguments. Values are hex encoded unless the 'encoding=base64' tag is given.
+func AttachBytesArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]byte) {
+	mustAttach(AttachBytesArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachBytesArgE is AttachBytesArg returning an error instead of panicking.
+func AttachBytesArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *[]byte) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachFileModeArg uses reflection to read the provided struct to determine the arguments. Values are octal such as '0644'.
+func AttachFileModeArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *os.FileMode) {
+	mustAttach(AttachFileModeArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachFileModeArgE is AttachFileModeArg returning an error instead of panicking.
+func AttachFileModeArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *os.FileMode) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachTimeArg uses reflection to read the provided struct to determine the arguments. Values are parsed with the 'layout' tag value (time.RFC3339 when absent) or one of the keywords 'now', 'today', 'yesterday' and 'tomorrow'.
+func AttachTimeArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *time.Time) {
+	mustAttach(AttachTimeArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachTimeArgE is AttachTimeArg returning an error instead of panicking.
+func AttachTimeArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *time.Time) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachIPArg uses reflection to read the provided struct to determine the arguments.
+func AttachIPArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *net.IP) {
+	mustAttach(AttachIPArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachIPArgE is AttachIPArg returning an error instead of panicking.
+func AttachIPArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *net.IP) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachIPNetArg uses reflection to read the provided struct to determine the arguments. Values are in CIDR notation such as '10.0.0.0/8'.
+func AttachIPNetArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *net.IPNet) {
+	mustAttach(AttachIPNetArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachIPNetArgE is AttachIPNetArg returning an error instead of panicking.
+func AttachIPNetArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *net.IPNet) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachURLArg uses reflection to read the provided struct to determine the arguments. When the 'schemes' tag is given (e.g. 'schemes=http|https') values with any other scheme are rejected.
+func AttachURLArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue **url.URL) {
+	mustAttach(AttachURLArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachURLArgE is AttachURLArg returning an error instead of panicking.
+func AttachURLArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue **url.URL) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachCountArg uses reflection to read the provided struct to determine the arguments. The value is incremented each time the flag is given, so '-vvv' yields 3. Struct fields select this with the 'type=count' tag.
+func AttachCountArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int) {
+	mustAttach(AttachCountArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachCountArgE is AttachCountArg returning an error instead of panicking.
+func AttachCountArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
+	}
+	arg.Type = "count"
+	return attachSingleFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
+}
+
+// AttachInt8Arg uses reflection to read the provided struct to determine the arguments.
+func AttachInt8Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int8) {
+	mustAttach(AttachInt8ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachInt8ArgE is AttachInt8Arg returning an error instead of panicking.
+func AttachInt8ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int8) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachInt16Arg uses reflection to read the provided struct to determine the arguments.
+func AttachInt16Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int16) {
+	mustAttach(AttachInt16ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachInt16ArgE is AttachInt16Arg returning an error instead of panicking.
+func AttachInt16ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int16) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachInt32Arg uses reflection to read the provided struct to determine the arguments.
+func AttachInt32Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int32) {
+	mustAttach(AttachInt32ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachInt32ArgE is AttachInt32Arg returning an error instead of panicking.
+func AttachInt32ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int32) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachInt64Arg uses reflection to read the provided struct to determine the arguments.
+func AttachInt64Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int64) {
+	mustAttach(AttachInt64ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachInt64ArgE is AttachInt64Arg returning an error instead of panicking.
+func AttachInt64ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int64) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachByteSizeArg uses reflection to read the provided struct to determine the arguments. Values are human-friendly sizes such as '512K', '10MB' or '2GiB'. Struct fields select this with the 'type=bytesize' tag.
+func AttachByteSizeArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int64) {
+	mustAttach(AttachByteSizeArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachByteSizeArgE is AttachByteSizeArg returning an error instead of panicking.
+func AttachByteSizeArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *int64) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
+	}
+	arg.Type = "bytesize"
+	return attachSingleFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
+}
+
+// AttachHostPortArg uses reflection to read the provided struct to determine the arguments. Values are host:port addresses such as 'localhost:8080', checked with ParseHostPort. Struct fields select this with the 'type=hostport' tag.
+func AttachHostPortArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *string) {
+	mustAttach(AttachHostPortArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachHostPortArgE is AttachHostPortArg returning an error instead of panicking.
+func AttachHostPortArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *string) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
+	}
+	arg.Type = "hostport"
+	return attachSingleFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
+}
+
+// AttachUintArg uses reflection to read the provided struct to determine the arguments.
+func AttachUintArg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint) {
+	mustAttach(AttachUintArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachUintArgE is AttachUintArg returning an error instead of panicking.
+func AttachUintArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachUint8Arg uses reflection to read the provided struct to determine the arguments.
+func AttachUint8Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint8) {
+	mustAttach(AttachUint8ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachUint8ArgE is AttachUint8Arg returning an error instead of panicking.
+func AttachUint8ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint8) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachUint16Arg uses reflection to read the provided struct to determine the arguments.
+func AttachUint16Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint16) {
+	mustAttach(AttachUint16ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachUint16ArgE is AttachUint16Arg returning an error instead of panicking.
+func AttachUint16ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint16) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachUint32Arg uses reflection to read the provided struct to determine the arguments.
+func AttachUint32Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint32) {
+	mustAttach(AttachUint32ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachUint32ArgE is AttachUint32Arg returning an error instead of panicking.
+func AttachUint32ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint32) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// AttachUint64Arg uses reflection to read the provided struct to determine the arguments.
+func AttachUint64Arg(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint64) {
+	mustAttach(AttachUint64ArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachUint64ArgE is AttachUint64Arg returning an error instead of panicking.
+func AttachUint64ArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *uint64) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// attachTypedArgE attaches variableValue using the same type inference as AttachStructArgs.
+func attachTypedArgE(cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue interface{}) error {
+	arg, rawHelp, err := parseArgE(parmType, variableName)
+	if err != nil {
+		return err
+	}
+	return attachSingleFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
+}
+
+func mustAttach(err error) {
+	if err != nil {
+		msg := fmt.Sprintf("Fatal mis-configuration. %v", err.Error())
+		panic(msg)
+	}
 }
 
 func rationalizeHelp(arg Argument, rawHelp string) (help string) {
@@ -182,28 +1082,164 @@ func rationalizeHelp(arg Argument, rawHelp string) (help string) {
 	return help
 }
 
-func parseArg(parmType reflect.Type, variableName string) (arg Argument, rawHelp string) {
-	var field reflect.StructField
-	var has bool
-	var err error
-	if field, has = parmType.FieldByName(variableName); !has {
-		msg := fmt.Sprintf("Fatal mis-configuration by the variable [%v]", variableName)
-		panic(msg)
+// parseArgE parses the tags of the field of parmType named variableName, taken from fieldArgCache when there.
+func parseArgE(parmType reflect.Type, variableName string) (arg Argument, rawHelp string, err error) {
+	key := fieldArgKey{structType: parmType, name: variableName}
+	generation := argCacheGeneration.Load()
+	if cached, has := fieldArgCache.Load(key); has && cached.(fieldArgEntry).generation == generation && !ExpandEnvDefaults() {
+		entry := cached.(fieldArgEntry)
+		return entry.arg, entry.rawHelp, entry.err
+	}
+	field, has := parmType.FieldByName(variableName)
+	if !has {
+		return arg, rawHelp, newArgError(ErrUnknownField, parmType, variableName, "", "", "struct %v has no field named [%v]", parmType.Name(), variableName)
 	}
 	arg, err = ParseArgFromField(field)
 	if err != nil {
-		msg := fmt.Sprintf("Fatal mis-configuration, could not get arguments from field [%v]", field)
-		panic(msg)
+		err = inStruct(err, parmType)
+	} else {
+		rawHelp = field.Tag.Get("help")
+	}
+	if !ExpandEnvDefaults() && !(arg.HasDefaultValue && arg.ExpandEnv) && argCacheGeneration.Load() == generation {
+		fieldArgCache.Store(key, fieldArgEntry{generation: generation, arg: arg, rawHelp: rawHelp, err: err})
 	}
-	rawHelp = field.Tag.Get("help")
-	return arg, rawHelp
+	return arg, rawHelp, err
 }
 
-func processRequiredArg(cmd *cobra.Command, arg Argument) {
-	if arg.Required {
-		if err := cmd.MarkFlagRequired(arg.LongName); err != nil {
-			msg := fmt.Sprintf("Fatal mis-configuration, could not mark required field: %v", err.Error())
-			panic(msg)
+// configureFlagArg applies the tag settings that act on an already registered flag rather than on its value, then installs the hooks on cmd that its secret, env, fromdir, validation, completion and required tags need.
+func configureFlagArg(cmd *cobra.Command, arg Argument) error {
+	if err := configureFlagSetArg(argFlags(cmd, arg), commandOwner(cmd), arg); err != nil {
+		return err
+	}
+	installSecretHook(cmd, arg)
+	installEnvHook(cmd, arg)
+	if err := bindValidationArgs(cmd, arg); err != nil {
+		return err
+	}
+	if err := attachCompleteArg(cmd, arg); err != nil {
+		return err
+	}
+	return markRequiredArg(cmd, arg)
+}
+
+// configureFlagSetArg applies the tag settings of arg that need only the flag set its flag is in, which belongs to owner, such as "command serve".
+func configureFlagSetArg(flags *pflag.FlagSet, owner string, arg Argument) error {
+	if err := flags.SetAnnotation(arg.LongName, attachedAnnotation, []string{"true"}); err != nil {
+		return err
+	}
+	if arg.Config != "" {
+		if err := flags.SetAnnotation(arg.LongName, configAnnotation, []string{arg.Config}); err != nil {
+			return err
+		}
+	}
+	if arg.HasNoOptDefault {
+		flags.Lookup(arg.LongName).NoOptDefVal = arg.NoOptDefault
+	}
+	if arg.Placeholder != "" {
+		// pflag renders the first `quoted` word of the usage in place of the type name
+		flag := flags.Lookup(arg.LongName)
+		flag.Usage = fmt.Sprintf("%v (`%v`)", strings.TrimSpace(flag.Usage), arg.Placeholder)
+	}
+	attachIndirectArg(flags, arg)
+	if err := markSecretArg(flags, arg); err != nil {
+		return err
+	}
+	if err := bindEnvArg(flags, arg); err != nil {
+		return err
+	}
+	if err := bindDirArg(flags, arg); err != nil {
+		return err
+	}
+	if err := attachAliasArgs(flags, owner, arg); err != nil {
+		return err
+	}
+	if err := attachNegatedArg(flags, owner, arg); err != nil {
+		return err
+	}
+	if arg.Required && len(argLongNames(arg)) == 1 {
+		if err := cobra.MarkFlagRequired(flags, arg.LongName); err != nil {
+			return err
+		}
+	}
+	if arg.Hidden {
+		if err := flags.MarkHidden(arg.LongName); err != nil {
+			return err
+		}
+	}
+	if arg.Deprecated != "" {
+		if err := flags.MarkDeprecated(arg.LongName, arg.Deprecated); err != nil {
+			return err
+		}
+	}
+	if arg.ShorthandDeprecated != "" {
+		return flags.MarkShorthandDeprecated(arg.LongName, arg.ShorthandDeprecated)
+	}
+	return nil
+}
+
+// markRequiredArg makes one of the names of a 'required' arg with aliases or a negated name required, its single name being marked by configureFlagSetArg.
+func markRequiredArg(cmd *cobra.Command, arg Argument) error {
+	if !arg.Required {
+		return nil
+	}
+	if longNames := argLongNames(arg); len(longNames) > 1 {
+		cmd.MarkFlagsOneRequired(longNames...)
+	}
+	return nil
+}
+
+// argLongNames returns every long flag name attached for arg: its own, its aliases and its negated name.
+func argLongNames(arg Argument) []string {
+	longNames := append([]string{arg.LongName}, arg.Aliases...)
+	if arg.Negatable {
+		longNames = append(longNames, negatedName(arg.LongName))
+	}
+	return longNames
+}
+
+func negatedName(longName string) string {
+	return "no-" + longName
+}
+
+// attachNegatedArg registers, for a 'negatable' bool flag, a hidden --no-<name> flag that sets the same variable to false.
+func attachNegatedArg(flags *pflag.FlagSet, owner string, arg Argument) error {
+	if !arg.Negatable {
+		return nil
+	}
+	flag := flags.Lookup(arg.LongName)
+	if flag.Value.Type() != "bool" {
+		return newArgError(ErrInvalidTagValue, nil, arg.LongName, "negatable", "true", "arg flag %v is 'negatable' but has type %v, not bool", arg.LongName, flag.Value.Type())
+	}
+	name := negatedName(arg.LongName)
+	if flags.Lookup(name) != nil {
+		return newArgError(ErrDuplicateName, nil, arg.LongName, "negatable", name, "arg flag %v has negated name [%v] which is already a flag on %v", arg.LongName, name, owner)
+	}
+	flags.AddFlag(&pflag.Flag{
+		Name:        name,
+		Usage:       fmt.Sprintf("sets --%v to false", arg.LongName),
+		Value:       &negatedBoolValue{Value: flag.Value, flag: flag},
+		DefValue:    "false",
+		NoOptDefVal: "true",
+		Hidden:      true,
+	})
+	return nil
+}
+
+// attachAliasArgs registers each 'aliases' name as a hidden flag sharing the value, and so the variable, of the flag named arg.LongName.
+func attachAliasArgs(flags *pflag.FlagSet, owner string, arg Argument) error {
+	flag := flags.Lookup(arg.LongName)
+	for _, alias := range arg.Aliases {
+		if flags.Lookup(alias) != nil {
+			return newArgError(ErrDuplicateName, nil, arg.LongName, "aliases", alias, "arg flag %v has alias [%v] which is already a flag on %v", arg.LongName, alias, owner)
 		}
+		flags.AddFlag(&pflag.Flag{
+			Name:        alias,
+			Usage:       fmt.Sprintf("alias for --%v", arg.LongName),
+			Value:       flag.Value,
+			DefValue:    flag.DefValue,
+			NoOptDefVal: flag.NoOptDefVal,
+			Hidden:      true,
+		})
 	}
+	return nil
 }
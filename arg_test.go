@@ -0,0 +1,62 @@
+package cobraargs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestNegatableArg(t *testing.T) {
+	type colorArgs struct {
+		Color bool `arg:"negatable,defaultvalue=true,env=TEST_NEGATABLE_COLOR"`
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		env    string
+		want   bool
+	}{
+		{name: "default", want: true},
+		{name: "flag", args: []string{"--color=false"}, want: false},
+		{name: "negated", args: []string{"--no-color"}, want: false},
+		{name: "negated false", args: []string{"--no-color=false"}, want: true},
+		{name: "config", config: "color: false\n", want: false},
+		{name: "negated over config", args: []string{"--no-color"}, config: "color: true\n", want: false},
+		{name: "negated over env", args: []string{"--no-color"}, env: "true", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.env != "" {
+				t.Setenv("TEST_NEGATABLE_COLOR", test.env)
+			}
+			var args colorArgs
+			var ran bool
+			cmd := &cobra.Command{Use: "paint", Run: func(*cobra.Command, []string) { ran = true }}
+			if err := AttachStructArgs(cmd, &args); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if test.config != "" {
+				if err := os.WriteFile(path, []byte(test.config), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := AttachConfigFlag(cmd, path); err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if !ran {
+				t.Fatal("command did not run")
+			}
+			if args.Color != test.want {
+				t.Errorf("Color = %v, want %v", args.Color, test.want)
+			}
+		})
+	}
+}
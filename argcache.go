@@ -0,0 +1,89 @@
+package cobraargs
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// structArg is an arg field found by structArgFields with the Argument parseStructArg reads from it, or the error doing so.
+type structArg struct {
+	structField
+	arg Argument
+	err error
+}
+
+// argCacheKey is a struct type with the Options that change how its tags parse.
+type argCacheKey struct {
+	structType reflect.Type
+	tagKey     string
+	helpTagKey string
+	nameStyle  NameStyle
+	dialect    TagDialect
+}
+
+// argCache holds the argCacheEntry of each struct type parsed without Options.Overrides, so attaching a struct with hundreds of fields, or one struct to many commands, reflects over it and parses its tags once. The Set and Register functions changing how tags parse clear it.
+var argCache sync.Map
+
+// argCacheGeneration counts the clears of argCache, so a parse racing a clear is never taken from it.
+var argCacheGeneration atomic.Uint64
+
+// argCacheEntry is a parse held by argCache with the generation it was started in.
+type argCacheEntry struct {
+	generation uint64
+	args       []structArg
+}
+
+// structArgs lists the arg fields of structType, as structArgFields does, each with its parsed Argument, taken from argCache when there. Structs with a default expanding environment variables are parsed every time, as the environment may have changed.
+func structArgs(structType reflect.Type, options Options) ([]structArg, error) {
+	key := argCacheKey{structType: structType, tagKey: options.TagKey, helpTagKey: options.HelpTagKey, nameStyle: options.NameStyle, dialect: options.Dialect}
+	cacheable := options.Overrides == nil && !ExpandEnvDefaults()
+	generation := argCacheGeneration.Load()
+	if cacheable {
+		if cached, has := argCache.Load(key); has && cached.(argCacheEntry).generation == generation {
+			return cached.(argCacheEntry).args, nil
+		}
+	}
+	fields, err := structArgFields(structType, options)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]structArg, len(fields))
+	for index, field := range fields {
+		arg, err := parseStructArg(field, options)
+		args[index] = structArg{structField: field, arg: arg, err: err}
+		cacheable = cacheable && !(arg.HasDefaultValue && arg.ExpandEnv)
+	}
+	if cacheable && argCacheGeneration.Load() == generation {
+		argCache.Store(key, argCacheEntry{generation: generation, args: args})
+	}
+	return args, nil
+}
+
+// fieldArgKey is a field named to the single-field functions, such as AttachIntArg.
+type fieldArgKey struct {
+	structType reflect.Type
+	name       string
+}
+
+// fieldArgCache holds the fieldArgEntry of each fieldArgKey parsed by parseArgE, cleared with argCache.
+var fieldArgCache sync.Map
+
+// fieldArgEntry is a parse held by fieldArgCache with the generation it was started in.
+type fieldArgEntry struct {
+	generation uint64
+	arg        Argument
+	rawHelp    string
+	err        error
+}
+
+// clearArgCache drops every parse held by argCache and fieldArgCache, including any being stored concurrently.
+func clearArgCache() {
+	argCacheGeneration.Add(1)
+	for _, cache := range []*sync.Map{&argCache, &fieldArgCache} {
+		cache.Range(func(key, _ interface{}) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
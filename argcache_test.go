@@ -0,0 +1,110 @@
+package cobraargs
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type completedArgs struct {
+	Zone string `arg:"complete=test-argcache-zones"`
+}
+
+// TestArgCacheRegisterCompleter checks that registering a completer drops the parses that rejected its name, for the struct and the single-field functions alike.
+func TestArgCacheRegisterCompleter(t *testing.T) {
+	structType := reflect.TypeOf(completedArgs{})
+	parses := []struct {
+		name  string
+		parse func() error
+	}{
+		{name: "structArgs", parse: func() error {
+			args, err := structArgs(structType, Options{})
+			if err != nil {
+				return err
+			}
+			return args[0].err
+		}},
+		{name: "parseArgE", parse: func() error {
+			_, _, err := parseArgE(structType, "Zone")
+			return err
+		}},
+	}
+	for _, parse := range parses {
+		if err := parse.parse(); err == nil {
+			t.Fatalf("%v accepted an unregistered completer", parse.name)
+		}
+	}
+	RegisterCompleter("test-argcache-zones", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"a", "b"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	for _, parse := range parses {
+		if err := parse.parse(); err != nil {
+			t.Errorf("%v after RegisterCompleter: %v", parse.name, err)
+		}
+	}
+}
+
+func TestParseArgECached(t *testing.T) {
+	type cachedArgs struct {
+		Name string `arg:"shortname=n,defaultvalue=bob" help:"the name"`
+	}
+	structType := reflect.TypeOf(cachedArgs{})
+	tests := []struct {
+		name     string
+		field    string
+		wantLong string
+		wantHelp string
+		wantErr  bool
+	}{
+		{name: "first parse", field: "Name", wantLong: "name", wantHelp: "the name"},
+		{name: "cached parse", field: "Name", wantLong: "name", wantHelp: "the name"},
+		{name: "unknown field", field: "Missing", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			arg, rawHelp, err := parseArgE(structType, test.field)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseArgE error %v, want error %v", err, test.wantErr)
+			}
+			if arg.LongName != test.wantLong || rawHelp != test.wantHelp {
+				t.Errorf("got %q %q, want %q %q", arg.LongName, rawHelp, test.wantLong, test.wantHelp)
+			}
+		})
+	}
+	if _, has := fieldArgCache.Load(fieldArgKey{structType: structType, name: "Name"}); !has {
+		t.Error("parseArgE did not cache the parse")
+	}
+}
+
+// TestArgCacheErrorsShared checks that attaching a misconfigured struct from many goroutines names the struct in its error without changing the cached parse error, which the race detector would otherwise report.
+func TestArgCacheErrorsShared(t *testing.T) {
+	type badCachedArgs struct {
+		Port int `arg:"shortname=toolong"`
+	}
+	var wait sync.WaitGroup
+	errs := make([]error, 8)
+	for index := range errs {
+		wait.Add(1)
+		go func(index int) {
+			defer wait.Done()
+			errs[index] = AttachStructArgs(&cobra.Command{Use: "app"}, &badCachedArgs{})
+		}(index)
+	}
+	wait.Wait()
+	for _, err := range errs {
+		var argError *ArgError
+		if !errors.As(err, &argError) || argError.Struct != "badCachedArgs" {
+			t.Errorf("AttachStructArgs error %v, want an ArgError naming struct badCachedArgs", err)
+		}
+	}
+	args, err := structArgs(reflect.TypeOf(badCachedArgs{}), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached := args[0].err.(*ArgError); cached.Struct != "" {
+		t.Errorf("cached parse error was annotated with struct %q", cached.Struct)
+	}
+}
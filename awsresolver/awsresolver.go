@@ -0,0 +1,207 @@
+// Package awsresolver resolves AWS Systems Manager Parameter Store and Secrets Manager references for cobraargs. Register it for fields tagged 'indirect=ssm|aws-secrets':
+//
+//	client := awsresolver.NewFromEnv()
+//	cobraargs.RegisterResolver("ssm", client.ParameterStore())
+//	cobraargs.RegisterResolver("aws-secrets", client.SecretsManager())
+//
+// after which ssm:/myapp/db_password is replaced by that decrypted parameter and aws-secrets:name by that secret string, or aws-secrets:name#field by one field of a JSON secret.
+package awsresolver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client calls the AWS JSON APIs with Signature Version 4 signed requests over net/http, so the AWS SDK is not needed.
+type Client struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials.
+	SessionToken string
+	// Endpoint overrides https://<service>.<region>.amazonaws.com, e.g. for LocalStack.
+	Endpoint string
+	// HTTPClient sends the requests, http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// NewFromEnv returns a Client configured from AWS_REGION (or AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func NewFromEnv() *Client {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return &Client{
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// ParameterStoreResolver resolves Parameter Store names, decrypting SecureString parameters.
+type ParameterStoreResolver struct {
+	Client *Client
+}
+
+// ParameterStore returns a resolver for the 'ssm' scheme.
+func (client *Client) ParameterStore() *ParameterStoreResolver {
+	return &ParameterStoreResolver{Client: client}
+}
+
+// Resolve returns the value of the parameter named reference.
+func (resolver *ParameterStoreResolver) Resolve(ctx context.Context, reference string) (string, error) {
+	var output struct {
+		Parameter struct {
+			Value string
+		}
+	}
+	input := map[string]interface{}{"Name": reference, "WithDecryption": true}
+	if err := resolver.Client.call(ctx, "ssm", "AmazonSSM.GetParameter", input, &output); err != nil {
+		return "", fmt.Errorf("ssm parameter %v: %v", reference, err)
+	}
+	return output.Parameter.Value, nil
+}
+
+// SecretsManagerResolver resolves Secrets Manager secret names or ARNs.
+type SecretsManagerResolver struct {
+	Client *Client
+}
+
+// SecretsManager returns a resolver for the 'aws-secrets' scheme.
+func (client *Client) SecretsManager() *SecretsManagerResolver {
+	return &SecretsManagerResolver{Client: client}
+}
+
+// Resolve returns the secret string of the secret named by reference, or with name#field the field of a JSON secret string.
+func (resolver *SecretsManagerResolver) Resolve(ctx context.Context, reference string) (string, error) {
+	name, field, hasField := strings.Cut(reference, "#")
+	var output struct {
+		SecretString string
+	}
+	if err := resolver.Client.call(ctx, "secretsmanager", "secretsmanager.GetSecretValue", map[string]interface{}{"SecretId": name}, &output); err != nil {
+		return "", fmt.Errorf("secret %v: %v", name, err)
+	}
+	if !hasField {
+		return output.SecretString, nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %v is not a JSON object: %v", name, err)
+	}
+	value, has := fields[field]
+	if !has {
+		return "", fmt.Errorf("secret %v has no field [%v]", name, field)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
+
+// call posts input to an AWS JSON 1.1 API action and decodes the response into output.
+func (client *Client) call(ctx context.Context, service, target string, input, output interface{}) error {
+	if client.Region == "" {
+		return fmt.Errorf("aws region is not set")
+	}
+	if client.AccessKeyID == "" || client.SecretAccessKey == "" {
+		return fmt.Errorf("aws credentials are not set")
+	}
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	endpoint := client.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%v.%v.amazonaws.com", service, client.Region)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", target)
+	client.sign(request, service, payload, time.Now().UTC())
+	httpClient := client.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		var failure struct {
+			Type         string `json:"__type"`
+			Message      string `json:"message"`
+			MessageUpper string `json:"Message"`
+		}
+		_ = json.Unmarshal(body, &failure)
+		return fmt.Errorf("%v %v %v%v", response.Status, failure.Type, failure.Message, failure.MessageUpper)
+	}
+	return json.Unmarshal(body, output)
+}
+
+// sign adds the Signature Version 4 headers for service to request, signing every header already set on it.
+func (client *Client) sign(request *http.Request, service string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	request.Header.Set("X-Amz-Date", amzDate)
+	if client.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", client.SessionToken)
+	}
+	signedHeaders := []string{"host"}
+	for name := range request.Header {
+		signedHeaders = append(signedHeaders, strings.ToLower(name))
+	}
+	sort.Strings(signedHeaders)
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%v:%v\n", name, strings.TrimSpace(value))
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	scope := strings.Join([]string{date, client.Region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+	key := []byte("AWS4" + client.SecretAccessKey)
+	for _, part := range []string{date, client.Region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v", client.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
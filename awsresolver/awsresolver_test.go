@@ -0,0 +1,113 @@
+package awsresolver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSign checks sign against the GET ListUsers example of the AWS Signature Version 4 documentation.
+func TestSign(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	client := &Client{Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	client.sign(request, "iam", nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := request.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization %q, want %q", got, want)
+	}
+	if got := request.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date %q, want 20150830T123600Z", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization := request.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(authorization, "x-amz-security-token") {
+			writer.WriteHeader(http.StatusForbidden)
+			writer.Write([]byte(`{"__type":"MissingAuthenticationTokenException","message":"no session token"}`))
+			return
+		}
+		var input map[string]interface{}
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch request.Header.Get("X-Amz-Target") + " " + requestedName(input) {
+		case "AmazonSSM.GetParameter /app/db_password":
+			writer.Write([]byte(`{"Parameter":{"Name":"/app/db_password","Value":"hunter2"}}`))
+		case "secretsmanager.GetSecretValue app":
+			writer.Write([]byte(`{"SecretString":"{\"user\":\"admin\",\"port\":5432}"}`))
+		case "secretsmanager.GetSecretValue plain":
+			writer.Write([]byte(`{"SecretString":"not json"}`))
+		default:
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte(`{"__type":"ResourceNotFoundException","Message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+	newClient := func() *Client {
+		return &Client{Region: "us-east-1", AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session", Endpoint: server.URL, HTTPClient: server.Client()}
+	}
+	tests := []struct {
+		name      string
+		scheme    string
+		reference string
+		configure func(*Client)
+		want      string
+		wantErr   string
+	}{
+		{name: "parameter", scheme: "ssm", reference: "/app/db_password", want: "hunter2"},
+		{name: "missing parameter", scheme: "ssm", reference: "/app/missing", wantErr: "ResourceNotFoundException not found"},
+		{name: "secret string", scheme: "aws-secrets", reference: "plain", want: "not json"},
+		{name: "secret field", scheme: "aws-secrets", reference: "app#user", want: "admin"},
+		{name: "non-string secret field", scheme: "aws-secrets", reference: "app#port", want: "5432"},
+		{name: "missing secret field", scheme: "aws-secrets", reference: "app#password", wantErr: "has no field [password]"},
+		{name: "field of non-JSON secret", scheme: "aws-secrets", reference: "plain#user", wantErr: "is not a JSON object"},
+		{name: "request rejected", scheme: "ssm", reference: "/app/db_password", configure: func(client *Client) { client.SessionToken = "" }, wantErr: "403 Forbidden MissingAuthenticationTokenException"},
+		{name: "missing region", scheme: "ssm", reference: "/app/db_password", configure: func(client *Client) { client.Region = "" }, wantErr: "aws region is not set"},
+		{name: "missing credentials", scheme: "aws-secrets", reference: "app", configure: func(client *Client) { client.SecretAccessKey = "" }, wantErr: "aws credentials are not set"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newClient()
+			if test.configure != nil {
+				test.configure(client)
+			}
+			resolve := client.ParameterStore().Resolve
+			if test.scheme == "aws-secrets" {
+				resolve = client.SecretsManager().Resolve
+			}
+			got, err := resolve(context.Background(), test.reference)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Resolve(%q) error %v, want one containing %q", test.reference, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("Resolve(%q) = %q, want %q", test.reference, got, test.want)
+			}
+		})
+	}
+}
+
+// requestedName returns the parameter Name or SecretId of a request.
+func requestedName(input map[string]interface{}) string {
+	if name, ok := input["Name"].(string); ok {
+		return name
+	}
+	name, _ := input["SecretId"].(string)
+	return name
+}
@@ -0,0 +1,394 @@
+// Command cobraargs-gen writes AttachArgs and UnmarshalArgs methods for arg structs, binding each field to its flag with plain pflag calls, so a binary attaching them needs neither reflection nor the cobraargs package at run time. Run it with go:generate in the package declaring the structs:
+//
+//	//go:generate go run github.com/doug4j/cobraargs/cmd/cobraargs-gen -type ServeOptions,LogOptions
+//
+// which writes serveoptions_args.go. The tags are read as cobraargs reads them. Fields whose type or tags need the cobraargs runtime, such as 'oneof', 'env', validations, nested structs or positional arguments, are reported as errors, so such a struct stays on AttachStructArgs.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug4j/cobraargs"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated names of the arg structs to generate for; required")
+	output := flag.String("output", "", "file to write, <first type in lower case>_args.go in the package directory by default")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: cobraargs-gen -type T[,T...] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	names := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(names[0])+"_args.go")
+	}
+	source, err := generate(dir, names, filepath.Base(*output))
+	if err == nil {
+		err = os.WriteFile(*output, source, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cobraargs-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes the source of the methods of the structs typeNames declared in the package in dir, ignoring its test files and the file being regenerated.
+func generate(dir string, typeNames []string, outputName string) ([]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fileSet := token.NewFileSet()
+	packageName := ""
+	structs := map[string]*ast.StructType{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == outputName {
+			continue
+		}
+		file, err := parser.ParseFile(fileSet, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		packageName = file.Name.Name
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.TypeSpec); ok {
+				if structType, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = structType
+				}
+			}
+			return true
+		})
+	}
+	var body bytes.Buffer
+	usesTime := false
+	var errs []error
+	for _, typeName := range typeNames {
+		structType, has := structs[typeName]
+		if !has {
+			errs = append(errs, fmt.Errorf("no struct type %v in %v", typeName, dir))
+			continue
+		}
+		fields, err := structFields(typeName, structType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, field := range fields {
+			usesTime = usesTime || strings.Contains(field.typeName, "time.")
+		}
+		writeMethods(&body, typeName, fields)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by cobraargs-gen; DO NOT EDIT.\n\npackage %v\n\nimport (\n\t\"fmt\"\n", packageName)
+	if usesTime {
+		source.WriteString("\t\"time\"\n")
+	}
+	source.WriteString("\n\t\"github.com/spf13/cobra\"\n)\n")
+	source.Write(body.Bytes())
+	return format.Source(source.Bytes())
+}
+
+// genField is an arg field with what its generated flag is made of.
+type genField struct {
+	name         string
+	typeName     string
+	arg          cobraargs.Argument
+	usage        string
+	binding      binding
+	defaultValue string
+}
+
+// binding is how a field type is bound: the pflag method stem, as String for StringVarP and GetString, and the Go literal of one default item.
+type binding struct {
+	method  string
+	literal func(text string) (string, error)
+	zero    string
+	list    bool
+}
+
+// countBinding binds an int field tagged type=count, which has no default.
+var countBinding = binding{method: "Count", zero: "0"}
+
+var bindings = map[string]binding{
+	"string":          {"String", stringLiteral, `""`, false},
+	"bool":            {"Bool", boolLiteral, "false", false},
+	"int":             {"Int", intLiteral(0), "0", false},
+	"int8":            {"Int8", intLiteral(8), "0", false},
+	"int16":           {"Int16", intLiteral(16), "0", false},
+	"int32":           {"Int32", intLiteral(32), "0", false},
+	"int64":           {"Int64", intLiteral(64), "0", false},
+	"uint":            {"Uint", uintLiteral(0), "0", false},
+	"uint8":           {"Uint8", uintLiteral(8), "0", false},
+	"uint16":          {"Uint16", uintLiteral(16), "0", false},
+	"uint32":          {"Uint32", uintLiteral(32), "0", false},
+	"uint64":          {"Uint64", uintLiteral(64), "0", false},
+	"float32":         {"Float32", floatLiteral(32), "0", false},
+	"float64":         {"Float64", floatLiteral(64), "0", false},
+	"time.Duration":   {"Duration", durationLiteral, "0", false},
+	"[]string":        {"StringArray", stringLiteral, "nil", true},
+	"[]int":           {"IntSlice", intLiteral(0), "nil", true},
+	"[]int64":         {"Int64Slice", intLiteral(64), "nil", true},
+	"[]float32":       {"Float32Slice", floatLiteral(32), "nil", true},
+	"[]float64":       {"Float64Slice", floatLiteral(64), "nil", true},
+	"[]time.Duration": {"DurationSlice", durationLiteral, "nil", true},
+}
+
+func stringLiteral(text string) (string, error) {
+	return strconv.Quote(text), nil
+}
+
+func boolLiteral(text string) (string, error) {
+	value, err := strconv.ParseBool(text)
+	return strconv.FormatBool(value), err
+}
+
+func intLiteral(bits int) func(string) (string, error) {
+	return func(text string) (string, error) {
+		value, err := strconv.ParseInt(text, 0, bits)
+		return strconv.FormatInt(value, 10), err
+	}
+}
+
+func uintLiteral(bits int) func(string) (string, error) {
+	return func(text string) (string, error) {
+		value, err := strconv.ParseUint(text, 0, bits)
+		return strconv.FormatUint(value, 10), err
+	}
+}
+
+func floatLiteral(bits int) func(string) (string, error) {
+	return func(text string) (string, error) {
+		value, err := strconv.ParseFloat(text, bits)
+		return strconv.FormatFloat(value, 'g', -1, bits), err
+	}
+}
+
+func durationLiteral(text string) (string, error) {
+	value, err := time.ParseDuration(text)
+	return fmt.Sprintf("time.Duration(%d)", value), err
+}
+
+// structFields reads the arg fields of the struct typeName, reporting those the generated code cannot bind.
+func structFields(typeName string, structType *ast.StructType) ([]genField, error) {
+	var fields []genField
+	var errs []error
+	for _, astField := range structType.Fields.List {
+		tag := ""
+		if astField.Tag != nil {
+			tag, _ = strconv.Unquote(astField.Tag.Value)
+		}
+		if len(astField.Names) == 0 {
+			if reflect.StructTag(tag).Get("arg") != "-" {
+				errs = append(errs, fmt.Errorf("field %v.%v is embedded, which needs cobraargs.AttachStructArgs", typeName, types.ExprString(astField.Type)))
+			}
+			continue
+		}
+		for _, name := range astField.Names {
+			field, isArg, err := structField(typeName, name.Name, types.ExprString(astField.Type), reflect.StructTag(tag))
+			if err != nil {
+				errs = append(errs, err)
+			} else if isArg {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields, errors.Join(errs...)
+}
+
+// structField reads one field, reporting false for a field that is not an arg field. An untagged field is an arg field when its type has a binding; untagged fields of other types are left alone, as their type is not known to bind without type checking.
+func structField(typeName, name, fieldType string, tag reflect.StructTag) (genField, bool, error) {
+	_, hasArg := tag.Lookup("arg")
+	rawHelp, hasHelp := tag.Lookup("help")
+	_, isSubcommand := tag.Lookup("cmd")
+	if _, known := bindings[fieldType]; !ast.IsExported(name) || isSubcommand || tag.Get("arg") == "-" || (!hasArg && !hasHelp && !known) {
+		return genField{}, false, nil
+	}
+	arg, err := cobraargs.ParseArgFromField(reflect.StructField{Name: name, Tag: tag})
+	if err != nil {
+		return genField{}, false, fmt.Errorf("field %v.%v: %v", typeName, name, err)
+	}
+	if tagKey := unsupportedTag(arg); tagKey != "" {
+		return genField{}, false, fmt.Errorf("field %v.%v has the '%v' tag, which needs cobraargs.AttachStructArgs", typeName, name, tagKey)
+	}
+	binding, known := bindings[fieldType]
+	if !known {
+		return genField{}, false, fmt.Errorf("field %v.%v has type %v, which needs cobraargs.AttachStructArgs", typeName, name, fieldType)
+	}
+	switch {
+	case arg.Type == "count" && fieldType == "int":
+		binding = countBinding
+	case arg.Type != "":
+		return genField{}, false, fmt.Errorf("field %v.%v has the 'type' tag %v, which needs cobraargs.AttachStructArgs", typeName, name, arg.Type)
+	case arg.Mode == "slice" && fieldType == "[]string":
+		binding.method = "StringSlice"
+	case arg.Mode != "":
+		return genField{}, false, fmt.Errorf("field %v.%v has the 'mode' tag %v, which needs cobraargs.AttachStructArgs", typeName, name, arg.Mode)
+	}
+	usage := "optional: " + rawHelp
+	if arg.Required {
+		usage = "MANDATORY: " + rawHelp
+	}
+	if arg.Placeholder != "" {
+		usage = fmt.Sprintf("%v (`%v`)", strings.TrimSpace(usage), arg.Placeholder)
+	}
+	field := genField{name: name, typeName: fieldType, arg: arg, usage: usage, binding: binding, defaultValue: binding.zero}
+	if arg.HasDefaultValue && binding.literal != nil {
+		if field.defaultValue, err = defaultLiteral(arg, fieldType, binding); err != nil {
+			return genField{}, false, fmt.Errorf("field %v.%v has default value [%v] which is not a %v", typeName, name, arg.DefaultValue, fieldType)
+		}
+	}
+	return field, true, nil
+}
+
+// defaultLiteral writes the 'defaultvalue' of arg as a Go literal of fieldType, list items separated as cobraargs separates them.
+func defaultLiteral(arg cobraargs.Argument, fieldType string, binding binding) (string, error) {
+	if !binding.list {
+		return binding.literal(arg.DefaultValue)
+	}
+	separator := arg.OnListSeparator
+	if separator == "" {
+		separator = cobraargs.DefaultValueOnListSeparator
+	}
+	items := strings.Split(arg.DefaultValue, separator)
+	literals := make([]string, len(items))
+	for index, item := range items {
+		literal, err := binding.literal(item)
+		if err != nil {
+			return "", err
+		}
+		literals[index] = literal
+	}
+	return fmt.Sprintf("%v{%v}", fieldType, strings.Join(literals, ", ")), nil
+}
+
+// unsupportedTag names the first tag of arg the generated code does not apply, or is empty when it applies them all.
+func unsupportedTag(arg cobraargs.Argument) string {
+	tags := []struct {
+		key string
+		set bool
+	}{
+		{"layout", arg.Layout != ""},
+		{"schemes", len(arg.Schemes) > 0},
+		{"encoding", arg.Encoding != ""},
+		{"oneof", len(arg.OneOf) > 0},
+		{"format", arg.Format != ""},
+		{"aliases", len(arg.Aliases) > 0},
+		{"negatable", arg.Negatable},
+		{"env", len(arg.Env) > 0},
+		{"config", arg.Config != ""},
+		{"secret", arg.Secret},
+		{"indirect", len(arg.Indirect) > 0},
+		{"expandenv", arg.ExpandEnv},
+		{"fromdir", arg.FromDir != ""},
+		{"requiredif", arg.RequiredIf != ""},
+		{"requiredunless", arg.RequiredUnless != ""},
+		{"xor", len(arg.Xor) > 0},
+		{"together", len(arg.Together) > 0},
+		{"onerequired", len(arg.OneRequired) > 0},
+		{"min", arg.Min != ""},
+		{"max", arg.Max != ""},
+		{"minlen", arg.MinLen != 0},
+		{"maxlen", arg.MaxLen != 0},
+		{"pattern", arg.Pattern != ""},
+		{"exists", arg.Exists != ""},
+		{"validate", len(arg.Validate) > 0},
+		{"requires", len(arg.Requires) > 0},
+		{"conflicts", len(arg.Conflicts) > 0},
+		{"positional", arg.Positional},
+		{"passthrough", arg.Passthrough},
+		{"prefix", arg.HasPrefix},
+		{"complete", arg.Complete != ""},
+	}
+	for _, tag := range tags {
+		if tag.set {
+			return tag.key
+		}
+	}
+	return ""
+}
+
+// writeMethods writes the AttachArgs and UnmarshalArgs methods of typeName.
+func writeMethods(w *bytes.Buffer, typeName string, fields []genField) {
+	var longNames, shortNames []string
+	for _, field := range fields {
+		longNames = append(longNames, strconv.Quote(field.arg.LongName))
+		if field.arg.ShortName != "" {
+			shortNames = append(shortNames, strconv.Quote(field.arg.ShortName))
+		}
+	}
+	fmt.Fprintf(w, "\n// AttachArgs attaches a flag to cmd for each arg field of %v, bound to the field, as cobraargs.AttachStructArgs does.\n", typeName)
+	fmt.Fprintf(w, "func (target *%v) AttachArgs(cmd *cobra.Command) error {\n", typeName)
+	fmt.Fprintf(w, "\tfor _, name := range []string{%v} {\n", strings.Join(longNames, ", "))
+	fmt.Fprintf(w, "\t\tif cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {\n")
+	fmt.Fprintf(w, "\t\t\treturn fmt.Errorf(\"struct %v has long name [%%v] which is already a flag on command %%v\", name, cmd.Name())\n\t\t}\n\t}\n", typeName)
+	if len(shortNames) > 0 {
+		fmt.Fprintf(w, "\tfor _, name := range []string{%v} {\n", strings.Join(shortNames, ", "))
+		fmt.Fprintf(w, "\t\tif cmd.Flags().ShorthandLookup(name) != nil || cmd.PersistentFlags().ShorthandLookup(name) != nil {\n")
+		fmt.Fprintf(w, "\t\t\treturn fmt.Errorf(\"struct %v has short name [%%v] which is already a flag on command %%v\", name, cmd.Name())\n\t\t}\n\t}\n", typeName)
+	}
+	for _, field := range fields {
+		flags := flagsOf(field)
+		name := strconv.Quote(field.arg.LongName)
+		if field.binding.method == "Count" {
+			fmt.Fprintf(w, "\t%v.CountVarP(&target.%v, %v, %q, %q)\n", flags, field.name, name, field.arg.ShortName, field.usage)
+		} else {
+			fmt.Fprintf(w, "\t%v.%vVarP(&target.%v, %v, %q, %v, %q)\n", flags, field.binding.method, field.name, name, field.arg.ShortName, field.defaultValue, field.usage)
+		}
+		if field.arg.HasNoOptDefault {
+			fmt.Fprintf(w, "\t%v.Lookup(%v).NoOptDefVal = %q\n", flags, name, field.arg.NoOptDefault)
+		}
+		if field.arg.Required {
+			fmt.Fprintf(w, "\tif err := cobra.MarkFlagRequired(%v, %v); err != nil {\n\t\treturn err\n\t}\n", flags, name)
+		}
+		if field.arg.Hidden {
+			fmt.Fprintf(w, "\tif err := %v.MarkHidden(%v); err != nil {\n\t\treturn err\n\t}\n", flags, name)
+		}
+		if field.arg.Deprecated != "" {
+			fmt.Fprintf(w, "\tif err := %v.MarkDeprecated(%v, %q); err != nil {\n\t\treturn err\n\t}\n", flags, name, field.arg.Deprecated)
+		}
+		if field.arg.ShorthandDeprecated != "" {
+			fmt.Fprintf(w, "\tif err := %v.MarkShorthandDeprecated(%v, %q); err != nil {\n\t\treturn err\n\t}\n", flags, name, field.arg.ShorthandDeprecated)
+		}
+	}
+	fmt.Fprintf(w, "\treturn nil\n}\n")
+	fmt.Fprintf(w, "\n// UnmarshalArgs sets the arg fields of %v from the flags parsed by cmd, as cobraargs.Unmarshal does.\n", typeName)
+	fmt.Fprintf(w, "func (target *%v) UnmarshalArgs(cmd *cobra.Command) error {\n\tvar err error\n", typeName)
+	for _, field := range fields {
+		fmt.Fprintf(w, "\tif target.%v, err = %v.Get%v(%q); err != nil {\n\t\treturn err\n\t}\n", field.name, flagsOf(field), field.binding.method, field.arg.LongName)
+	}
+	fmt.Fprintf(w, "\treturn nil\n}\n")
+}
+
+// flagsOf is the expression of the flag set the flag of field is in.
+func flagsOf(field genField) string {
+	if field.arg.Persistent {
+		return "cmd.PersistentFlags()"
+	}
+	return "cmd.Flags()"
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+		wantErr  string
+	}{
+		{
+			name:     "tagged fields",
+			source:   "type Opts struct {\n\tName string `arg:\"shortname=n,defaultvalue=bob\" help:\"the name\"`\n\tVerbose int `arg:\"shortname=v,type=count\"`\n}\n",
+			contains: []string{`StringVarP(&target.Name, "name", "n", "bob", "optional: the name")`, `CountVarP(&target.Verbose, "verbose", "v"`},
+		},
+		{
+			name:     "untagged fields",
+			source:   "type Opts struct {\n\tName string\n\tTimeout time.Duration\n\tOnDone func()\n\tSkipped string `arg:\"-\"`\n\tSub Other `cmd:\"sub\"`\n\tinternal string\n}\n",
+			contains: []string{`StringVarP(&target.Name, "name"`, `DurationVarP(&target.Timeout, "timeout"`},
+			excludes: []string{"OnDone", "Skipped", "Sub", "internal"},
+		},
+		{
+			name:    "tagged unknown type",
+			source:  "type Opts struct {\n\tOnDone func() `help:\"x\"`\n}\n",
+			wantErr: "needs cobraargs.AttachStructArgs",
+		},
+		{
+			name:    "runtime tag",
+			source:  "type Opts struct {\n\tLevel string `arg:\"oneof=debug|info\"`\n}\n",
+			wantErr: "'oneof' tag",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "opts.go"), []byte("package opts\n\nimport \"time\"\n\nvar _ time.Duration\n\ntype Other struct{}\n\n"+test.source), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			source, err := generate(dir, []string{"Opts"}, "opts_args.go")
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("generate error %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.contains {
+				if !strings.Contains(string(source), want) {
+					t.Errorf("generated source does not contain %q:\n%s", want, source)
+				}
+			}
+			for _, unwanted := range test.excludes {
+				if strings.Contains(string(source), unwanted) {
+					t.Errorf("generated source contains %q:\n%s", unwanted, source)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package cobraargs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Command marks a params struct as describing a whole command for NewCommand. Embed it with a 'cmd' tag holding the command settings, as in `cmd:"use=serve,short='Start the server',aliases=s|srv"`, using the same key=value syntax as the 'arg' tag. The keys are use, short, long, example, aliases, version, hidden and deprecated, and a bare first item is taken as use, as in `cmd:"serve"`.
+type Command struct{}
+
+// CommandTagKey names the tag read from the embedded Command marker.
+const CommandTagKey = "cmd"
+
+var commandType = reflect.TypeOf(Command{})
+
+// NewCommand builds a cobra.Command from the params struct target points to: its settings come from the 'cmd' tag of an embedded Command marker, and every field flag is attached as by AttachStructArgs. Without a marker, or without a use key, the command is named after the struct in lower case. A struct or struct pointer field with a 'cmd' tag, as in `cmd:"serve"`, becomes a subcommand built the same way from the field, its tag settings taking precedence over the marker of the field's type; the flags of a struct with such fields are then persistent, shared by all its subcommands. Set RunE on the results, or use Bind, to run them.
+func NewCommand(target interface{}) (*cobra.Command, error) {
+	return newCommand(target, Options{})
+}
+
+// NewCommandWithOptions is NewCommand reading the tags named by options.
+func NewCommandWithOptions(target interface{}, options Options) (*cobra.Command, error) {
+	return newCommand(target, options)
+}
+
+func newCommand(target interface{}, options Options) (*cobra.Command, error) {
+	return buildCommand(target, "", nil, options)
+}
+
+// buildCommand is newCommand also applying fieldTag, the 'cmd' tag of the field target is a subcommand of, and adding the command to parent. A command is added to its parent, and its flags attached, before its subcommands are built, so the flags of a subcommand are checked against the persistent flags it inherits.
+func buildCommand(target interface{}, fieldTag string, parent *cobra.Command, options Options) (*cobra.Command, error) {
+	structValue, err := structPointerValue(target)
+	if err != nil {
+		return nil, err
+	}
+	structType := structValue.Type()
+	cmd := &cobra.Command{Use: strings.ToLower(structType.Name())}
+	if field, ok := commandMarkerField(structType); ok {
+		if err := applyCommandTag(cmd, structType, field.Tag.Get(CommandTagKey)); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyCommandTag(cmd, structType, fieldTag); err != nil {
+		return nil, err
+	}
+	var subTags []string
+	var subTargets []interface{}
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		subTag, isSub := field.Tag.Lookup(CommandTagKey)
+		if !isSub || field.Type == commandType {
+			continue
+		}
+		subTarget, ok := subcommandTarget(structValue.Field(index))
+		if !ok {
+			return nil, fmt.Errorf("field %v.%v has a '%v' tag but is of type %v rather than a struct or struct pointer", structType.Name(), field.Name, CommandTagKey, field.Type)
+		}
+		subTags = append(subTags, subTag)
+		subTargets = append(subTargets, subTarget)
+	}
+	if parent != nil {
+		parent.AddCommand(cmd)
+	}
+	options.persistent = len(subTargets) > 0
+	if err := attachStructArgs(cmd, target, options); err != nil {
+		return nil, err
+	}
+	options.persistent = false
+	for index, subTarget := range subTargets {
+		if _, err := buildCommand(subTarget, subTags[index], cmd, options); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// subcommandTarget returns a pointer to the struct a subcommand field holds, allocating a nil struct pointer.
+func subcommandTarget(fieldValue reflect.Value) (interface{}, bool) {
+	switch {
+	case fieldValue.Kind() == reflect.Struct:
+		return fieldValue.Addr().Interface(), true
+	case fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct:
+		if fieldValue.IsNil() {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
+		return fieldValue.Interface(), true
+	}
+	return nil, false
+}
+
+// commandMarkerField finds the Command marker of a params struct.
+func commandMarkerField(structType reflect.Type) (reflect.StructField, bool) {
+	for index := 0; index < structType.NumField(); index++ {
+		if field := structType.Field(index); field.Type == commandType {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// applyCommandTag sets the fields of cmd named by a 'cmd' tag.
+func applyCommandTag(cmd *cobra.Command, structType reflect.Type, rawTag string) error {
+	if rawTag == "" {
+		return nil
+	}
+	items, err := splitArgTag(rawTag)
+	if err != nil {
+		return fmt.Errorf("struct %v has a '%v' tag with %v: [%v]", structType.Name(), CommandTagKey, err, rawTag)
+	}
+	for index, item := range items {
+		name := strings.ToLower(item.Name)
+		if !item.HasValue {
+			if index == 0 && name != "hidden" {
+				cmd.Use = item.Name
+				continue
+			}
+			item.Value = "true"
+		}
+		switch name {
+		case "use":
+			cmd.Use = item.Value
+		case "short":
+			cmd.Short = item.Value
+		case "long":
+			cmd.Long = item.Value
+		case "example":
+			cmd.Example = item.Value
+		case "aliases":
+			cmd.Aliases = strings.Split(item.Value, "|")
+		case "version":
+			cmd.Version = item.Value
+		case "deprecated":
+			cmd.Deprecated = item.Value
+		case "hidden":
+			hidden, err := strconv.ParseBool(item.Value)
+			if err != nil {
+				return fmt.Errorf("struct %v has '%v' tag key 'hidden' which is not a boolean, it's [%v]", structType.Name(), CommandTagKey, item.Value)
+			}
+			cmd.Hidden = hidden
+		default:
+			return fmt.Errorf("struct %v has unknown '%v' tag key [%v]", structType.Name(), CommandTagKey, item.Name)
+		}
+	}
+	return nil
+}
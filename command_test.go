@@ -0,0 +1,89 @@
+package cobraargs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type serveParams struct {
+	Command `cmd:"serve"`
+	Port    int `arg:"defaultvalue=8080"`
+}
+
+type clashParams struct {
+	Command `cmd:"serve"`
+	Version bool `arg:"shortname=v"`
+}
+
+type rootParams struct {
+	Verbose bool        `arg:"shortname=v"`
+	Serve   serveParams `cmd:"serve"`
+}
+
+type clashRootParams struct {
+	Verbose bool        `arg:"shortname=v"`
+	Serve   clashParams `cmd:"serve"`
+}
+
+type badSubParams struct {
+	Serve int `cmd:"serve"`
+}
+
+func TestNewCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  interface{}
+		args    []string
+		wantErr error
+		check   func(t *testing.T, target interface{})
+	}{
+		{
+			name:   "subcommand inherits persistent flags",
+			target: &rootParams{},
+			args:   []string{"serve", "-v", "--port", "9090"},
+			check: func(t *testing.T, target interface{}) {
+				params := target.(*rootParams)
+				if !params.Verbose || params.Serve.Port != 9090 {
+					t.Errorf("got %+v", params)
+				}
+			},
+		},
+		{
+			name:    "short name of a persistent flag",
+			target:  &clashRootParams{},
+			wantErr: ErrDuplicateName,
+		},
+		{
+			name:   "subcommand field not a struct",
+			target: &badSubParams{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := NewCommand(test.target)
+			if test.check == nil {
+				if err == nil {
+					t.Fatal("NewCommand succeeded, want an error")
+				}
+				var argErr *ArgError
+				if test.wantErr != nil && (!errors.Is(err, test.wantErr) || !errors.As(err, &argErr)) {
+					t.Fatalf("NewCommand error %v, want an ArgError of %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, sub := range cmd.Commands() {
+				sub.Run = func(*cobra.Command, []string) {}
+			}
+			cmd.SetArgs(test.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			test.check(t, test.target)
+		})
+	}
+}
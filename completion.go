@@ -0,0 +1,257 @@
+package cobraargs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// completeAnnotation records the 'complete' tag of a flag for the completion specs.
+const completeAnnotation = "cobraargs_complete"
+
+// completionTimeout bounds the exec: and http: completion sources, so a slow source cannot hang the shell.
+const completionTimeout = 5 * time.Second
+
+// CompleteFunc completes the value of a flag, as a cobra flag completion function does.
+type CompleteFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+var (
+	completersLock sync.RWMutex
+	completers     = map[string]CompleteFunc{}
+)
+
+// RegisterCompleter makes fields tagged 'complete=<name>' complete their flag with fn. Register before attaching, as the 'complete' tag only accepts known names. The names files, dirs and none and the exec: and http: sources are built in and cannot be replaced.
+func RegisterCompleter(name string, fn CompleteFunc) {
+	completersLock.Lock()
+	defer completersLock.Unlock()
+	completers[name] = fn
+	clearArgCache()
+}
+
+func lookupCompleter(name string) (CompleteFunc, bool) {
+	completersLock.RLock()
+	defer completersLock.RUnlock()
+	fn, has := completers[name]
+	return fn, has
+}
+
+var completionBaseURL atomic.Value
+
+// SetCompletionBaseURL sets, for the whole process, the URL that the paths of 'complete=http:' sources are relative to, as in https://api.example.com for `arg:"complete=http:GET /api/choices"`.
+func SetCompletionBaseURL(baseURL string) {
+	completionBaseURL.Store(baseURL)
+}
+
+// CompletionBaseURL reports the URL set by SetCompletionBaseURL.
+func CompletionBaseURL() string {
+	baseURL, _ := completionBaseURL.Load().(string)
+	return baseURL
+}
+
+// isBuiltinCompletion reports whether complete is files, files:<ext>|<ext>, dirs, none, exec:<command> or http:<method> <path>.
+func isBuiltinCompletion(complete string) bool {
+	switch {
+	case complete == "files" || strings.HasPrefix(complete, "files:") || complete == "dirs" || complete == "none":
+		return true
+	case strings.HasPrefix(complete, "exec:"):
+		return len(strings.Fields(strings.TrimPrefix(complete, "exec:"))) > 0
+	case strings.HasPrefix(complete, "http:"):
+		_, _, err := parseHTTPCompletion(strings.TrimPrefix(complete, "http:"))
+		return err == nil
+	}
+	return false
+}
+
+// parseHTTPCompletion splits the source of a 'complete=http:' tag, as in GET /api/choices, into its method and target.
+func parseHTTPCompletion(source string) (string, string, error) {
+	parts := strings.Fields(source)
+	if len(parts) != 2 || parts[0] != strings.ToUpper(parts[0]) {
+		return "", "", fmt.Errorf("http completion source [%v] is not of the form METHOD /path", source)
+	}
+	return parts[0], parts[1], nil
+}
+
+// attachCompleteArg wires the 'complete' tag of arg, else its 'oneof' choices, into shell completion of its flag.
+func attachCompleteArg(cmd *cobra.Command, arg Argument) error {
+	flags := argFlags(cmd, arg)
+	if arg.Complete == "" {
+		if len(arg.OneOf) == 0 {
+			return nil
+		}
+		return cmd.RegisterFlagCompletionFunc(arg.LongName, argCompletion(arg)) // the 'oneof' choices
+	}
+	if err := flags.SetAnnotation(arg.LongName, completeAnnotation, []string{arg.Complete}); err != nil {
+		return err
+	}
+	switch {
+	case arg.Complete == "files":
+		return cobra.MarkFlagFilename(flags, arg.LongName)
+	case strings.HasPrefix(arg.Complete, "files:"):
+		return cobra.MarkFlagFilename(flags, arg.LongName, strings.Split(strings.TrimPrefix(arg.Complete, "files:"), "|")...)
+	case arg.Complete == "dirs":
+		return cobra.MarkFlagDirname(flags, arg.LongName)
+	}
+	return cmd.RegisterFlagCompletionFunc(arg.LongName, argCompletion(arg))
+}
+
+// argCompletion returns the completion of the values of arg from its 'complete' tag, else from its 'oneof' choices, or nil when it has neither.
+func argCompletion(arg Argument) CompleteFunc {
+	switch {
+	case arg.Complete == "":
+		if len(arg.OneOf) == 0 {
+			return nil
+		}
+		choices := arg.OneOf
+		if len(arg.ChoiceHelp) > 0 {
+			choices = make([]string, len(arg.OneOf))
+			for index, choice := range arg.OneOf {
+				choices[index] = choice + "\t" + arg.ChoiceHelp[index]
+			}
+		}
+		return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return choices, cobra.ShellCompDirectiveNoFileComp
+		}
+	case arg.Complete == "files":
+		return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+	case strings.HasPrefix(arg.Complete, "files:"):
+		extensions := strings.Split(strings.TrimPrefix(arg.Complete, "files:"), "|")
+		return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return extensions, cobra.ShellCompDirectiveFilterFileExt
+		}
+	case arg.Complete == "dirs":
+		return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+	case arg.Complete == "none":
+		return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	if command := strings.TrimPrefix(arg.Complete, "exec:"); command != arg.Complete {
+		return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completeFromSource(completeExec(cmd.Context(), strings.Fields(command)))
+		}
+	}
+	if source := strings.TrimPrefix(arg.Complete, "http:"); source != arg.Complete {
+		method, target, _ := parseHTTPCompletion(source)
+		return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completeFromSource(completeHTTP(cmd.Context(), method, target))
+		}
+	}
+	name := arg.Complete
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		complete, has := lookupCompleter(name)
+		if !has {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeFromSource turns the choices of an exec: or http: source into a completion, reporting a failure of the source on stderr as cobra does.
+func completeFromSource(choices []string, err error) ([]string, cobra.ShellCompDirective) {
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return choices, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeExec runs command, offering each non-empty line of its output as a choice. A tab separates a choice from its description, as in cobra completions.
+func completeExec(ctx context.Context, command []string) ([]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, command[0], command[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("exec:%v failed: %v", command[0], err)
+	}
+	return completionLines(bytes.NewReader(output))
+}
+
+// completeHTTP requests target, relative to CompletionBaseURL unless absolute, offering the choices of the response: a JSON array of strings, a JSON array of objects with value and description, or else each non-empty line of the body.
+func completeHTTP(ctx context.Context, method, target string) ([]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	endpoint, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.IsAbs() {
+		baseURL := CompletionBaseURL()
+		if baseURL == "" {
+			return nil, fmt.Errorf("http completion of %v needs SetCompletionBaseURL", target)
+		}
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = base.ResolveReference(endpoint)
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http completion %v %v failed: %v", method, endpoint, response.Status)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if err := json.Unmarshal(body, &values); err == nil {
+		return values, nil
+	}
+	var described []struct {
+		Value       string `json:"value"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &described); err == nil {
+		choices := make([]string, 0, len(described))
+		for _, choice := range described {
+			if choice.Description == "" {
+				choices = append(choices, choice.Value)
+				continue
+			}
+			choices = append(choices, choice.Value+"\t"+choice.Description)
+		}
+		return choices, nil
+	}
+	return completionLines(bytes.NewReader(body))
+}
+
+func completionLines(reader io.Reader) ([]string, error) {
+	var choices []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			choices = append(choices, line)
+		}
+	}
+	return choices, scanner.Err()
+}
@@ -0,0 +1,130 @@
+package cobraargs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TestCompletionHelperProcess is the command run by the exec: completion tests, printing its arguments as choices or sleeping past their deadline.
+func TestCompletionHelperProcess(t *testing.T) {
+	if os.Getenv("TEST_COMPLETION_HELPER") != "1" {
+		return
+	}
+	args := os.Args[len(os.Args)-1:]
+	switch args[0] {
+	case "choices":
+		fmt.Print("alpha\n\n  beta\tthe second  \ngamma\n")
+	case "sleep":
+		time.Sleep(10 * time.Second)
+	default:
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+func TestCompleteExec(t *testing.T) {
+	t.Setenv("TEST_COMPLETION_HELPER", "1")
+	tests := []struct {
+		name    string
+		action  string
+		timeout time.Duration
+		want    []string
+		wantErr bool
+	}{
+		{name: "output lines", action: "choices", want: []string{"alpha", "beta\tthe second", "gamma"}},
+		{name: "command fails", action: "fail", wantErr: true},
+		{name: "timeout", action: "sleep", timeout: 100 * time.Millisecond, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+				defer cancel()
+			}
+			got, err := completeExec(ctx, []string{os.Args[0], "-test.run=TestCompletionHelperProcess", "--", test.action})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("completeExec error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("completeExec = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCompleteHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method + " " + request.URL.Path {
+		case "GET /strings":
+			writer.Write([]byte(`["eu-west-1","us-east-1"]`))
+		case "GET /described":
+			writer.Write([]byte(`[{"value":"small","description":"1 CPU"},{"value":"large"}]`))
+		case "POST /lines":
+			writer.Write([]byte("one\n\ntwo\n"))
+		case "GET /slow":
+			select {
+			case <-request.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		default:
+			http.Error(writer, "no such choices", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		baseURL string
+		timeout time.Duration
+		want    []string
+		wantErr bool
+	}{
+		{name: "JSON strings", method: "GET", target: "/strings", baseURL: server.URL, want: []string{"eu-west-1", "us-east-1"}},
+		{name: "JSON objects", method: "GET", target: "/described", baseURL: server.URL, want: []string{"small\t1 CPU", "large"}},
+		{name: "lines", method: "POST", target: "/lines", baseURL: server.URL, want: []string{"one", "two"}},
+		{name: "absolute target", method: "GET", target: server.URL + "/strings", want: []string{"eu-west-1", "us-east-1"}},
+		{name: "server error", method: "GET", target: "/missing", baseURL: server.URL, wantErr: true},
+		{name: "no base URL", method: "GET", target: "/strings", wantErr: true},
+		{name: "timeout", method: "GET", target: "/slow", baseURL: server.URL, timeout: 100 * time.Millisecond, wantErr: true},
+	}
+	defer SetCompletionBaseURL("")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetCompletionBaseURL(test.baseURL)
+			ctx := context.Background()
+			if test.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+				defer cancel()
+			}
+			got, err := completeHTTP(ctx, test.method, test.target)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("completeHTTP error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("completeHTTP = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArgCompletionSourceError(t *testing.T) {
+	defer SetCompletionBaseURL("")
+	SetCompletionBaseURL("")
+	complete := argCompletion(Argument{Complete: "http:GET /choices"})
+	choices, directive := complete(&cobra.Command{Use: "app"}, nil, "")
+	if choices != nil || directive != cobra.ShellCompDirectiveError {
+		t.Errorf("completion of a failing source = %q, %v, want no choices and ShellCompDirectiveError", choices, directive)
+	}
+}
@@ -0,0 +1,329 @@
+package cobraargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// completionHint is what a completion spec can express of the completion of a flag or positional field: its choices, file or directory names, or the output of a command. The http: sources and registered completers run in the program itself and have no hint.
+type completionHint struct {
+	choices      []string
+	descriptions []string
+	files        bool
+	extensions   []string
+	dirs         bool
+	command      string
+}
+
+// argCompletionHint reads the hint of a field from its 'complete' tag, else from its 'oneof' choices.
+func argCompletionHint(arg Argument) completionHint {
+	switch {
+	case arg.Complete == "":
+		return completionHint{choices: arg.OneOf, descriptions: arg.ChoiceHelp}
+	case arg.Complete == "files":
+		return completionHint{files: true}
+	case strings.HasPrefix(arg.Complete, "files:"):
+		return completionHint{files: true, extensions: strings.Split(strings.TrimPrefix(arg.Complete, "files:"), "|")}
+	case arg.Complete == "dirs":
+		return completionHint{dirs: true}
+	case strings.HasPrefix(arg.Complete, "exec:"):
+		return completionHint{command: strings.Join(strings.Fields(strings.TrimPrefix(arg.Complete, "exec:")), " ")}
+	}
+	return completionHint{}
+}
+
+// flagCompletionHint reads the hint of a flag from its annotations and 'oneof' choices, so flags marked by cobra.MarkFlagFilename or cobra.MarkFlagDirname get one too.
+func flagCompletionHint(flag *pflag.Flag) completionHint {
+	arg := Argument{}
+	if complete := flag.Annotations[completeAnnotation]; len(complete) > 0 {
+		arg.Complete = complete[0]
+	}
+	if oneOf := flagOneOf(flag); oneOf != nil {
+		arg.OneOf, arg.ChoiceHelp = oneOf.choices, oneOf.descriptions
+	}
+	if arg.Complete != "" || len(arg.OneOf) > 0 {
+		return argCompletionHint(arg)
+	}
+	if extensions, has := flag.Annotations[cobra.BashCompFilenameExt]; has {
+		return completionHint{files: true, extensions: extensions}
+	}
+	if _, has := flag.Annotations[cobra.BashCompSubdirsInDir]; has {
+		return completionHint{dirs: true}
+	}
+	return completionHint{}
+}
+
+// flagOneOf finds the oneOfValue among the wrappedValue layers of flag, nil when it has no 'oneof' tag.
+func flagOneOf(flag *pflag.Flag) *oneOfValue {
+	for value := flag.Value; ; {
+		if oneOf, ok := value.(*oneOfValue); ok {
+			return oneOf
+		}
+		wrapped, ok := value.(wrappedValue)
+		if !ok {
+			return nil
+		}
+		value = wrapped.unwrap()
+	}
+}
+
+// flagTakesValue reports whether flag needs a value and whether the value may be left out, as for flags with a NoOptDefVal.
+func flagTakesValue(flag *pflag.Flag) (takesValue, optional bool) {
+	if flag.Value.Type() == "bool" || flag.Value.Type() == "count" {
+		return false, false
+	}
+	return true, flag.NoOptDefVal != ""
+}
+
+// flagRepeatable reports whether flag may be given more than once, adding to its value.
+func flagRepeatable(flag *pflag.Flag) bool {
+	if flag.Value.Type() == "count" {
+		return true
+	}
+	_, isSlice := unwrapValue(flag.Value).(pflag.SliceValue)
+	return isSlice
+}
+
+func flagRequired(flag *pflag.Flag) bool {
+	required := flag.Annotations[cobra.BashCompOneRequiredFlag]
+	return len(required) > 0 && required[0] == "true"
+}
+
+// specFlags visits the flags a spec lists for cmd: its local flags, then the persistent flags it declares, skipping the ones it inherits.
+func specFlags(cmd *cobra.Command, visit func(flag *pflag.Flag, persistent bool)) {
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		visit(flag, false)
+	})
+	cmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		visit(flag, true)
+	})
+}
+
+// carapaceSpec is a command in the carapace-spec YAML format.
+type carapaceSpec struct {
+	Name            string            `yaml:"name"`
+	Aliases         []string          `yaml:"aliases,omitempty"`
+	Description     string            `yaml:"description,omitempty"`
+	Hidden          bool              `yaml:"hidden,omitempty"`
+	Flags           map[string]string `yaml:"flags,omitempty"`
+	PersistentFlags map[string]string `yaml:"persistentflags,omitempty"`
+	Completion      carapaceActions   `yaml:"completion,omitempty"`
+	Commands        []carapaceSpec    `yaml:"commands,omitempty"`
+}
+
+type carapaceActions struct {
+	Flag          map[string][]string `yaml:"flag,omitempty"`
+	Positional    [][]string          `yaml:"positional,omitempty"`
+	PositionalAny []string            `yaml:"positionalany,omitempty"`
+	DashAny       []string            `yaml:"dashany,omitempty"`
+}
+
+// WriteCarapaceSpec writes a carapace-spec YAML completion spec of cmd and its subcommands to w, with the 'oneof' choices, the 'choicehelp' descriptions, the file and directory hints and the exec: sources of the flags and positional fields. Completions from http: sources and registered completers run in the program, so the spec leaves them out.
+func WriteCarapaceSpec(w io.Writer, cmd *cobra.Command) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(newCarapaceSpec(cmd)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func newCarapaceSpec(cmd *cobra.Command) carapaceSpec {
+	spec := carapaceSpec{Name: cmd.Name(), Aliases: cmd.Aliases, Description: cmd.Short, Hidden: cmd.Hidden}
+	flagActions := map[string][]string{}
+	specFlags(cmd, func(flag *pflag.Flag, persistent bool) {
+		name := "--" + flag.Name
+		if flag.Shorthand != "" {
+			name = "-" + flag.Shorthand + ", " + name
+		}
+		if flagRepeatable(flag) {
+			name += "*"
+		}
+		if takesValue, optional := flagTakesValue(flag); optional {
+			name += "?"
+		} else if takesValue {
+			name += "="
+		}
+		if flagRequired(flag) {
+			name += "!"
+		}
+		if flag.Hidden {
+			name += "&"
+		}
+		if persistent {
+			if spec.PersistentFlags == nil {
+				spec.PersistentFlags = map[string]string{}
+			}
+			spec.PersistentFlags[name] = flag.Usage
+		} else {
+			if spec.Flags == nil {
+				spec.Flags = map[string]string{}
+			}
+			spec.Flags[name] = flag.Usage
+		}
+		if actions := carapaceActionsOf(flagCompletionHint(flag)); len(actions) > 0 {
+			flagActions[flag.Name] = actions
+		}
+	})
+	if len(flagActions) > 0 {
+		spec.Completion.Flag = flagActions
+	}
+	if positional, has := lookupPositionalArgs(cmd); has {
+		for _, arg := range positional.args {
+			spec.Completion.Positional = append(spec.Completion.Positional, carapaceActionsOf(argCompletionHint(arg)))
+		}
+		if positional.rest != nil {
+			spec.Completion.PositionalAny = carapaceActionsOf(argCompletionHint(*positional.rest))
+		}
+		if positional.passthrough != nil {
+			spec.Completion.DashAny = carapaceActionsOf(argCompletionHint(*positional.passthrough))
+		}
+	}
+	for _, subcommand := range cmd.Commands() {
+		spec.Commands = append(spec.Commands, newCarapaceSpec(subcommand))
+	}
+	return spec
+}
+
+// carapaceActionsOf writes hint as carapace values and macros, such as $files([.yaml, .yml]).
+func carapaceActionsOf(hint completionHint) []string {
+	switch {
+	case hint.files && len(hint.extensions) > 0:
+		extensions := make([]string, len(hint.extensions))
+		for index, extension := range hint.extensions {
+			extensions[index] = "." + strings.TrimPrefix(extension, ".")
+		}
+		return []string{"$files([" + strings.Join(extensions, ", ") + "])"}
+	case hint.files:
+		return []string{"$files"}
+	case hint.dirs:
+		return []string{"$directories"}
+	case hint.command != "":
+		return []string{"$(" + hint.command + ")"}
+	}
+	actions := make([]string, len(hint.choices))
+	for index, choice := range hint.choices {
+		actions[index] = choice
+		if index < len(hint.descriptions) {
+			actions[index] += "\t" + hint.descriptions[index]
+		}
+	}
+	return actions
+}
+
+// figSpec is a command in the Fig completion spec format.
+type figSpec struct {
+	Name        interface{} `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Hidden      bool        `json:"hidden,omitempty"`
+	Subcommands []figSpec   `json:"subcommands,omitempty"`
+	Options     []figOption `json:"options,omitempty"`
+	Args        []figArg    `json:"args,omitempty"`
+}
+
+type figOption struct {
+	Name         []string `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	IsRequired   bool     `json:"isRequired,omitempty"`
+	IsRepeatable bool     `json:"isRepeatable,omitempty"`
+	IsPersistent bool     `json:"isPersistent,omitempty"`
+	Hidden       bool     `json:"hidden,omitempty"`
+	Args         *figArg  `json:"args,omitempty"`
+}
+
+type figArg struct {
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	IsOptional  bool            `json:"isOptional,omitempty"`
+	IsVariadic  bool            `json:"isVariadic,omitempty"`
+	Suggestions []figSuggestion `json:"suggestions,omitempty"`
+	Template    string          `json:"template,omitempty"`
+	Generators  *figGenerator   `json:"generators,omitempty"`
+}
+
+type figSuggestion struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type figGenerator struct {
+	Script  []string `json:"script"`
+	SplitOn string   `json:"splitOn"`
+}
+
+// WriteFigSpec writes a Fig completion spec of cmd and its subcommands to w as a TypeScript module, with the 'oneof' choices, the 'choicehelp' descriptions, the file and directory hints and the exec: sources of the flags and positional fields. Completions from http: sources and registered completers run in the program, so the spec leaves them out.
+func WriteFigSpec(w io.Writer, cmd *cobra.Command) error {
+	spec, err := json.MarshalIndent(newFigSpec(cmd), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "const completionSpec: Fig.Spec = %s;\n\nexport default completionSpec;\n", spec)
+	return err
+}
+
+func newFigSpec(cmd *cobra.Command) figSpec {
+	spec := figSpec{Name: cmd.Name(), Description: cmd.Short, Hidden: cmd.Hidden}
+	if len(cmd.Aliases) > 0 {
+		spec.Name = append([]string{cmd.Name()}, cmd.Aliases...)
+	}
+	specFlags(cmd, func(flag *pflag.Flag, persistent bool) {
+		option := figOption{Name: []string{"--" + flag.Name}, Description: flag.Usage, IsRequired: flagRequired(flag), IsRepeatable: flagRepeatable(flag), IsPersistent: persistent, Hidden: flag.Hidden}
+		if flag.Shorthand != "" {
+			option.Name = []string{"-" + flag.Shorthand, "--" + flag.Name}
+		}
+		if takesValue, optional := flagTakesValue(flag); takesValue {
+			arg := figArgOf(flagCompletionHint(flag))
+			arg.Name, arg.IsOptional = flag.Value.Type(), optional
+			option.Args = &arg
+		}
+		spec.Options = append(spec.Options, option)
+	})
+	if positional, has := lookupPositionalArgs(cmd); has {
+		for _, arg := range positional.args {
+			spec.Args = append(spec.Args, positional.figArg(arg, false))
+		}
+		if positional.rest != nil {
+			spec.Args = append(spec.Args, positional.figArg(*positional.rest, true))
+		}
+		if positional.passthrough != nil {
+			spec.Args = append(spec.Args, positional.figArg(*positional.passthrough, true))
+		}
+	}
+	for _, subcommand := range cmd.Commands() {
+		spec.Subcommands = append(spec.Subcommands, newFigSpec(subcommand))
+	}
+	return spec
+}
+
+func (positional *positionalArgs) figArg(arg Argument, variadic bool) figArg {
+	figArg := figArgOf(argCompletionHint(arg))
+	figArg.Name, figArg.Description, figArg.IsVariadic = positionalName(arg), positional.holder.Flags().Lookup(arg.LongName).Usage, variadic
+	return figArg
+}
+
+// figArgOf writes hint as Fig suggestions, templates or a generator running the exec: command.
+func figArgOf(hint completionHint) figArg {
+	switch {
+	case hint.files:
+		return figArg{Template: "filepaths"}
+	case hint.dirs:
+		return figArg{Template: "folders"}
+	case hint.command != "":
+		return figArg{Generators: &figGenerator{Script: strings.Fields(hint.command), SplitOn: "\n"}}
+	}
+	var arg figArg
+	for index, choice := range hint.choices {
+		suggestion := figSuggestion{Name: choice}
+		if index < len(hint.descriptions) {
+			suggestion.Description = hint.descriptions[index]
+		}
+		arg.Suggestions = append(arg.Suggestions, suggestion)
+	}
+	return arg
+}
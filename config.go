@@ -0,0 +1,189 @@
+package cobraargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFlagName is the long name of the flag declared by AttachConfigFlag.
+const ConfigFlagName = "config"
+
+// configAnnotation is the flag annotation holding the dotted 'config' key path a flag reads from the config file instead of its long name.
+const configAnnotation = "cobraargs_config"
+
+// configHookAnnotation marks a command whose PersistentPreRunE already loads the config file.
+const configHookAnnotation = "cobraargs_config_hook"
+
+// AttachConfigFlag declares a persistent --config flag on cmd naming a YAML, JSON or TOML file, picked by its extension, and installs a PersistentPreRunE hook that fills every attached flag not given on the command line from it. The chain orders the sources consulted after the command line and must name SourceEnv and SourceConfig once each; it defaults to SourceEnv, SourceConfig, giving flag > env > config file > tag default. A missing file at defaultPath is ignored but a missing file named with --config is an error. Subcommands whose own PersistentPreRunE the struct functions install still load the file first, but a subcommand given a PersistentPreRun of its own after attaching needs cobra.EnableTraverseRunHooks set.
+func AttachConfigFlag(cmd *cobra.Command, defaultPath string, chain ...Source) error {
+	if len(chain) == 0 {
+		chain = []Source{SourceEnv, SourceConfig}
+	}
+	if len(chain) != 2 || chain[0] == chain[1] || !isChainSource(chain[0]) || !isChainSource(chain[1]) {
+		return fmt.Errorf("config source chain %v must name %v and %v once each", chain, SourceEnv, SourceConfig)
+	}
+	if cmd.Flags().Lookup(ConfigFlagName) != nil || cmd.PersistentFlags().Lookup(ConfigFlagName) != nil {
+		return fmt.Errorf("command %v already has a --%v flag", cmd.Name(), ConfigFlagName)
+	}
+	cmd.PersistentFlags().String(ConfigFlagName, defaultPath, "optional: config file (`FILE`) in YAML, JSON or TOML")
+	installPreRunHook(cmd, configHookAnnotation, true, func(cmd *cobra.Command) error {
+		return applySourceChain(cmd, chain)
+	})
+	return nil
+}
+
+func isChainSource(source Source) bool {
+	return source == SourceEnv || source == SourceConfig
+}
+
+// applySourceChain fills the attached flags of cmd not given on the command line from each source of chain in turn.
+func applySourceChain(cmd *cobra.Command, chain []Source) error {
+	for _, source := range chain {
+		var err error
+		switch source {
+		case SourceEnv:
+			err = ApplyEnvArgs(cmd)
+		case SourceConfig:
+			err = applyConfigFile(cmd)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyConfigFile(cmd *cobra.Command) error {
+	configFlag := cmd.Flag(ConfigFlagName)
+	if configFlag == nil || configFlag.Value.String() == "" {
+		return nil
+	}
+	values, err := readConfigFile(configFlag.Value.String())
+	if errors.Is(err, fs.ErrNotExist) && !configFlag.Changed {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return applyConfigValues(cmd, values, SourceConfig, "config file "+configFlag.Value.String())
+}
+
+// applyConfigValues sets every attached flag of cmd not yet set from its 'config' key path, or long name, in values, recording source and naming origin in errors.
+func applyConfigValues(cmd *cobra.Command, values map[string]interface{}, source Source, origin string) error {
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Annotations[attachedAnnotation] == nil {
+			return
+		}
+		key := flag.Name
+		if paths := flag.Annotations[configAnnotation]; len(paths) > 0 {
+			key = paths[0]
+		}
+		value, has := lookupConfigValue(values, key)
+		if !has {
+			return
+		}
+		if setErr := setFlagFromConfig(cmd.Flags(), flag, value, source); setErr != nil {
+			if isSecretFlag(flag) {
+				setErr = secretSetError(flag)
+			}
+			err = fmt.Errorf("%v key %v: %v", origin, key, setErr)
+		}
+	})
+	return err
+}
+
+// lookupConfigValue finds a dotted key path such as server.port in decoded config values, trying the whole key first so flat keys containing dots still match.
+func lookupConfigValue(values map[string]interface{}, key string) (interface{}, bool) {
+	if value, has := values[key]; has {
+		return value, true
+	}
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		return nil, false
+	}
+	child, ok := values[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupConfigValue(child, rest)
+}
+
+// readConfigFile decodes a YAML (.yaml, .yml), JSON (.json) or TOML (.toml) file into a map.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("config file %v is not .yaml, .yml, .json or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %v: %v", path, err)
+	}
+	return values, nil
+}
+
+// setFlagFromConfig sets a flag from a decoded config value. Lists replace the whole value of slice flags and maps become key=value pairs.
+func setFlagFromConfig(flags *pflag.FlagSet, flag *pflag.Flag, value interface{}, source Source) error {
+	if items, ok := value.([]interface{}); ok {
+		values := make([]string, len(items))
+		for index, item := range items {
+			values[index] = configString(item)
+		}
+		sliceValue, ok := flag.Value.(pflag.SliceValue)
+		if !ok {
+			return setFlagFromSource(flags, flag, strings.Join(values, ","), source)
+		}
+		if err := sliceValue.Replace(values); err != nil {
+			return err
+		}
+		flag.Changed = true
+		return flags.SetAnnotation(flag.Name, sourceAnnotation, []string{string(source)})
+	}
+	return setFlagFromSource(flags, flag, configString(value), source)
+}
+
+func configString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for index, key := range keys {
+			pairs[index] = fmt.Sprintf("%v=%v", key, configString(value[key]))
+		}
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
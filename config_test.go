@@ -0,0 +1,71 @@
+package cobraargs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAttachConfigFlagSubcommands(t *testing.T) {
+	type rootArgs struct {
+		Region string `arg:"persistent,defaultvalue=local"`
+	}
+	type groupArgs struct {
+		Token string `arg:"persistent,env=TEST_CONFIG_TOKEN"`
+	}
+	type leafArgs struct {
+		Name string `arg:"defaultvalue=none"`
+	}
+	tests := []struct {
+		name       string
+		userHook   bool
+		args       []string
+		wantRegion string
+		wantName   string
+		wantHook   bool
+	}{
+		{name: "subcommand hooks", args: []string{"group", "leaf"}, wantRegion: "eu", wantName: "from-config"},
+		{name: "flag over config", args: []string{"group", "leaf", "--region", "us"}, wantRegion: "us", wantName: "from-config"},
+		{name: "user hook kept", userHook: true, args: []string{"group", "leaf"}, wantRegion: "eu", wantName: "from-config", wantHook: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte("region: eu\nname: from-config\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			var root rootArgs
+			var group groupArgs
+			var leaf leafArgs
+			var hookRan bool
+			rootCmd := &cobra.Command{Use: "app"}
+			groupCmd := &cobra.Command{Use: "group"}
+			if test.userHook {
+				groupCmd.PersistentPreRun = func(*cobra.Command, []string) { hookRan = true }
+			}
+			leafCmd := &cobra.Command{Use: "leaf", Run: func(*cobra.Command, []string) {}}
+			rootCmd.AddCommand(groupCmd)
+			groupCmd.AddCommand(leafCmd)
+			for cmd, target := range map[*cobra.Command]interface{}{rootCmd: &root, groupCmd: &group, leafCmd: &leaf} {
+				if err := AttachStructArgs(cmd, target); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := AttachConfigFlag(rootCmd, path); err != nil {
+				t.Fatal(err)
+			}
+			rootCmd.SetArgs(test.args)
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if root.Region != test.wantRegion || leaf.Name != test.wantName {
+				t.Errorf("got region %q name %q, want %q %q", root.Region, leaf.Name, test.wantRegion, test.wantName)
+			}
+			if hookRan != test.wantHook {
+				t.Errorf("PersistentPreRun ran %v, want %v", hookRan, test.wantHook)
+			}
+		})
+	}
+}
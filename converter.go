@@ -0,0 +1,82 @@
+package cobraargs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type registeredConverter struct {
+	parse  func(string) (interface{}, error)
+	format func(interface{}) string
+}
+
+var (
+	convertersLock sync.RWMutex
+	converters     = map[reflect.Type]registeredConverter{}
+)
+
+// RegisterConverter registers how values of fieldType are parsed from and formatted to flag text. AttachStructArgs, AttachArg and default value parsing consult registered converters before the built-in types, so registering a built-in type overrides it. A nil format uses fmt.Sprint.
+func RegisterConverter(fieldType reflect.Type, parse func(string) (interface{}, error), format func(interface{}) string) {
+	if format == nil {
+		format = func(value interface{}) string {
+			return fmt.Sprint(value)
+		}
+	}
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+	converters[fieldType] = registeredConverter{parse: parse, format: format}
+	clearArgCache()
+}
+
+func lookupConverter(fieldType reflect.Type) (registeredConverter, bool) {
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+	converter, has := converters[fieldType]
+	return converter, has
+}
+
+// converterValue is a pflag.Value backed by a registered converter.
+type converterValue struct {
+	value     reflect.Value
+	converter registeredConverter
+}
+
+func converterValueOf(variableValue interface{}) (*converterValue, bool) {
+	pointer := reflect.ValueOf(variableValue)
+	converter, has := lookupConverter(pointer.Type().Elem())
+	if !has {
+		return nil, false
+	}
+	return &converterValue{value: pointer, converter: converter}, true
+}
+
+func (c *converterValue) Set(val string) error {
+	parsed, err := c.converter.parse(val)
+	if err != nil {
+		return err
+	}
+	parsedValue := reflect.ValueOf(parsed)
+	fieldType := c.value.Type().Elem()
+	if !parsedValue.IsValid() {
+		parsedValue = reflect.Zero(fieldType)
+	}
+	if !parsedValue.Type().AssignableTo(fieldType) {
+		return fmt.Errorf("converter for %v returned a %v", fieldType, parsedValue.Type())
+	}
+	c.value.Elem().Set(parsedValue)
+	return nil
+}
+
+func (c *converterValue) String() string {
+	return c.converter.format(c.value.Elem().Interface())
+}
+
+func (c *converterValue) Type() string {
+	return strings.ToLower(c.value.Type().Elem().Name())
+}
+
+func (c *converterValue) fieldPointer() reflect.Value {
+	return c.value
+}
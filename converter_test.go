@@ -0,0 +1,63 @@
+package cobraargs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type testLevel int
+
+func TestRegisterConverter(t *testing.T) {
+	levels := []string{"low", "mid", "high"}
+	RegisterConverter(reflect.TypeOf(testLevel(0)), func(text string) (interface{}, error) {
+		for index, name := range levels {
+			if strings.EqualFold(name, text) {
+				return testLevel(index), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown level %q", text)
+	}, func(value interface{}) string {
+		return levels[value.(testLevel)]
+	})
+	type levelArgs struct {
+		Level testLevel `arg:"defaultvalue=mid"`
+		Floor testLevel `help:"lowest level"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    testLevel
+		floor   testLevel
+		wantErr bool
+	}{
+		{name: "default", want: 1},
+		{name: "set", args: []string{"--level", "HIGH"}, want: 2},
+		{name: "zero value", args: []string{"--floor", "high"}, want: 1, floor: 2},
+		{name: "bad value", args: []string{"--level", "max"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args levelArgs
+			cmd := &cobra.Command{Use: "level", Run: func(*cobra.Command, []string) {}}
+			cmd.SilenceErrors, cmd.SilenceUsage = true, true
+			if err := AttachStructArgs(cmd, &args); err != nil {
+				t.Fatal(err)
+			}
+			if flag := cmd.Flags().Lookup("level"); flag.DefValue != "mid" {
+				t.Errorf("default shown as %q, want mid", flag.DefValue)
+			}
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Execute error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && (args.Level != test.want || args.Floor != test.floor) {
+				t.Errorf("got %+v", args)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cobraargs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TagDialect is the tag syntax the struct functions read, so structs written for other flag libraries can be reused unchanged.
+type TagDialect int
+
+const (
+	// NativeDialect reads the cobraargs key=value items of the 'arg' tag, or of Options.TagKey.
+	NativeDialect TagDialect = iota
+	// GoArgDialect reads github.com/alexflint/go-arg tags, as in `arg:"-n,--name,required,env:NAME" default:"x" help:"..."`. Every exported field is a flag unless tagged `arg:"-"`, named by its field name in kebab case, and subcommand fields are skipped.
+	GoArgDialect
+	// KongDialect reads github.com/alecthomas/kong tags, as in `name:"x" short:"n" default:"1" enum:"a,b" env:"X" required:"" help:"..."` or the same in one `kong:"name='x',short='n'"` tag. Every exported field is a flag unless tagged `kong:"-"`, named by its field name in kebab case, `arg:""` fields are positional and `embed:""` structs are nested with their 'prefix'.
+	KongDialect
+)
+
+// argTag returns the cobraargs 'arg' tag of field, translated from the tags of options.Dialect, and whether the field has one.
+func (options Options) argTag(field reflect.StructField) (string, bool) {
+	switch options.Dialect {
+	case GoArgDialect:
+		return goArgTag(field)
+	case KongDialect:
+		return kongTag(field)
+	}
+	return field.Tag.Lookup(options.tagKey())
+}
+
+// isDialectStruct reports whether field is a struct the dialects descend into, or skip, rather than bind as a flag.
+func isDialectStruct(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Struct && !isFlagStructType(field.Type)
+}
+
+// goArgValueKeys pairs the go-arg tags read besides 'arg' and 'help' with the cobraargs keys they become.
+var goArgValueKeys = [][2]string{{"default", "defaultvalue"}, {"placeholder", "placeholder"}}
+
+func goArgTag(field reflect.StructField) (string, bool) {
+	raw := field.Tag.Get("arg")
+	if raw == "-" || strings.HasPrefix(raw, "subcommand") || strings.Contains(raw, ",subcommand") {
+		return "-", true
+	}
+	if isDialectStruct(field) {
+		return "", false
+	}
+	items := []string{}
+	hasLongName := false
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case strings.HasPrefix(item, "--"):
+			items = append(items, "longname="+escapeTagValue(strings.TrimPrefix(item, "--")))
+			hasLongName = true
+		case strings.HasPrefix(item, "-"):
+			items = append(items, "shortname="+escapeTagValue(strings.TrimPrefix(item, "-")))
+		case item == "required", item == "positional":
+			items = append(items, item)
+		case item == "env":
+			items = append(items, "env="+escapeTagValue(strings.ToUpper(field.Name)))
+		case strings.HasPrefix(item, "env:"):
+			items = append(items, "env="+escapeTagValue(strings.TrimPrefix(item, "env:")))
+		}
+	}
+	if !hasLongName {
+		items = append(items, "longname="+escapeTagValue(longNameOf(field.Name, KebabCase)))
+	}
+	for _, key := range goArgValueKeys {
+		if value, has := field.Tag.Lookup(key[0]); has {
+			items = append(items, key[1]+"="+escapeTagValue(value))
+		}
+	}
+	return strings.Join(items, ","), true
+}
+
+// kongTagKeys lists the kong tag keys translated into cobraargs items.
+var kongTagKeys = []string{"name", "short", "default", "required", "env", "enum", "placeholder", "hidden", "sep", "aliases", "xor", "and", "arg", "negatable", "type", "embed", "prefix"}
+
+// kongValueKeys, kongListKeys and kongFlagKeys pair kong tag keys with the cobraargs keys they become, for values, comma separated lists and bare keys.
+var (
+	kongValueKeys = [][2]string{{"short", "shortname"}, {"default", "defaultvalue"}, {"placeholder", "placeholder"}, {"sep", "sep"}}
+	kongListKeys  = [][2]string{{"env", "env"}, {"enum", "oneof"}, {"aliases", "aliases"}, {"xor", "xor"}, {"and", "together"}}
+	kongFlagKeys  = [][2]string{{"required", "required"}, {"hidden", "hidden"}, {"negatable", "negatable"}, {"arg", "positional"}}
+)
+
+func kongTag(field reflect.StructField) (string, bool) {
+	tags := map[string]string{}
+	for _, key := range kongTagKeys {
+		if value, has := field.Tag.Lookup(key); has {
+			tags[key] = value
+		}
+	}
+	if raw, has := field.Tag.Lookup("kong"); has {
+		if raw == "-" {
+			return "-", true
+		}
+		kongItems, err := splitArgTag(raw)
+		if err != nil {
+			return raw, true // reported by the tag parser
+		}
+		for _, item := range kongItems {
+			tags[strings.TrimSpace(item.Name)] = item.Value
+		}
+	}
+	if isDialectStruct(field) {
+		if _, embedded := tags["embed"]; embedded {
+			return "prefix=" + escapeTagValue(tags["prefix"]), true
+		}
+		return "", false
+	}
+	name, hasName := tags["name"]
+	if !hasName {
+		name = longNameOf(field.Name, KebabCase)
+	}
+	items := []string{"longname=" + escapeTagValue(name)}
+	for _, key := range kongValueKeys {
+		if value, has := tags[key[0]]; has {
+			items = append(items, key[1]+"="+escapeTagValue(value))
+		}
+	}
+	for _, key := range kongListKeys {
+		if value, has := tags[key[0]]; has {
+			items = append(items, key[1]+"="+escapeTagValue(strings.ReplaceAll(value, ",", "|")))
+		}
+	}
+	for _, key := range kongFlagKeys {
+		if _, has := tags[key[0]]; has {
+			items = append(items, key[1])
+		}
+	}
+	switch tags["type"] {
+	case "path":
+		items = append(items, "complete=files")
+	case "existingfile":
+		items = append(items, "exists=file")
+	case "existingdir":
+		items = append(items, "exists=dir")
+	}
+	return strings.Join(items, ","), true
+}
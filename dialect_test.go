@@ -0,0 +1,72 @@
+package cobraargs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDialectArgTag(t *testing.T) {
+	type goArgArgs struct {
+		Name    string    `arg:"-n,--user-name,required,env:APP_NAME" default:"bob" help:"the name"`
+		MaxSize int       `default:"10"`
+		Skipped string    `arg:"-"`
+		Sub     *struct{} `arg:"subcommand:sub"`
+	}
+	type kongArgs struct {
+		Name   string `name:"user" short:"n" default:"bob" required:""`
+		Level  string `enum:"debug,info" default:"info"`
+		Color  bool   `negatable:""`
+		Joined string `kong:"name='joined-name',short='j'"`
+		File   string `arg:""`
+		Hidden string `kong:"-"`
+	}
+	tests := []struct {
+		name    string
+		dialect TagDialect
+		target  interface{}
+		field   string
+		want    Argument
+		skipped bool
+	}{
+		{name: "go-arg names", dialect: GoArgDialect, target: goArgArgs{}, field: "Name", want: Argument{LongName: "user-name", ShortName: "n", Required: true, Env: []string{"APP_NAME"}, DefaultValue: "bob", HasDefaultValue: true}},
+		{name: "go-arg untagged", dialect: GoArgDialect, target: goArgArgs{}, field: "MaxSize", want: Argument{LongName: "max-size", DefaultValue: "10", HasDefaultValue: true}},
+		{name: "go-arg skipped", dialect: GoArgDialect, target: goArgArgs{}, field: "Skipped", skipped: true},
+		{name: "go-arg subcommand", dialect: GoArgDialect, target: goArgArgs{}, field: "Sub", skipped: true},
+		{name: "kong tags", dialect: KongDialect, target: kongArgs{}, field: "Name", want: Argument{LongName: "user", ShortName: "n", Required: true, DefaultValue: "bob", HasDefaultValue: true}},
+		{name: "kong enum", dialect: KongDialect, target: kongArgs{}, field: "Level", want: Argument{LongName: "level", OneOf: []string{"debug", "info"}, DefaultValue: "info", HasDefaultValue: true}},
+		{name: "kong negatable", dialect: KongDialect, target: kongArgs{}, field: "Color", want: Argument{LongName: "color", Negatable: true}},
+		{name: "kong single tag", dialect: KongDialect, target: kongArgs{}, field: "Joined", want: Argument{LongName: "joined-name", ShortName: "j"}},
+		{name: "kong positional", dialect: KongDialect, target: kongArgs{}, field: "File", want: Argument{LongName: "file", Positional: true}},
+		{name: "kong skipped", dialect: KongDialect, target: kongArgs{}, field: "Hidden", skipped: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := Options{Dialect: test.dialect}
+			fields, err := structArgFields(reflect.TypeOf(test.target), options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var found *structField
+			for index := range fields {
+				if fields[index].Name == test.field {
+					found = &fields[index]
+				}
+			}
+			if (found == nil) != test.skipped {
+				t.Fatalf("field %v found %v, want skipped %v", test.field, found != nil, test.skipped)
+			}
+			if found == nil {
+				return
+			}
+			got, err := parseStructArg(*found, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.LongName != test.want.LongName || got.ShortName != test.want.ShortName || got.Required != test.want.Required ||
+				got.DefaultValue != test.want.DefaultValue || got.HasDefaultValue != test.want.HasDefaultValue || got.Negatable != test.want.Negatable ||
+				got.Positional != test.want.Positional || !reflect.DeepEqual(got.Env, test.want.Env) || !reflect.DeepEqual(got.OneOf, test.want.OneOf) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package cobraargs
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagDoc is one flag of an arg struct as the documentation generators describe it.
+type flagDoc struct {
+	longName     string
+	shortName    string
+	valueType    string
+	takesValue   bool
+	defaultValue string
+	required     bool
+	help         string
+	arg          Argument
+	flag         *pflag.Flag
+}
+
+// structFlagDocs lists the flags of structType (a struct or pointer to struct) in field order, read from a command the struct is attached to so types and defaults match the real flags.
+func structFlagDocs(structType reflect.Type) ([]flagDoc, error) {
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type [%v] is not a struct", structType)
+	}
+	cmd := &cobra.Command{Use: structType.Name()}
+	if err := AttachStructArgs(cmd, reflect.New(structType).Interface()); err != nil {
+		return nil, err
+	}
+	fields, err := structArgs(structType, Options{})
+	if err != nil {
+		return nil, err
+	}
+	var docs []flagDoc
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			return nil, inStruct(err, field.parent)
+		}
+		if arg.Positional {
+			continue
+		}
+		flag := argFlags(cmd, arg).Lookup(arg.LongName)
+		doc := flagDoc{longName: flag.Name, shortName: flag.Shorthand, valueType: flag.Value.Type(), defaultValue: flag.DefValue, required: arg.Required, help: strings.TrimSpace(field.Tag.Get("help")), arg: arg, flag: flag}
+		doc.takesValue = doc.valueType != "bool" && doc.valueType != "count"
+		if arg.Placeholder != "" {
+			doc.valueType = arg.Placeholder
+		}
+		if isSecretFlag(flag) && doc.defaultValue != "" {
+			doc.defaultValue = RedactedValue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// GenMarkdownFlags writes a Markdown table of the flags of structType (a struct or pointer to struct) to w, giving the long name, short name, type, default, whether it is required and the help text of each, straight from the struct tags, for embedding in project docs without running the binary.
+func GenMarkdownFlags(structType reflect.Type, w io.Writer) error {
+	docs, err := structFlagDocs(structType)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, "| Flag | Short | Type | Default | Required | Description |\n| --- | --- | --- | --- | --- | --- |\n"); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		shortName, defaultValue, required := "", "", "no"
+		if doc.shortName != "" {
+			shortName = "`-" + doc.shortName + "`"
+		}
+		if doc.defaultValue != "" {
+			defaultValue = "`" + doc.defaultValue + "`"
+		}
+		if doc.required {
+			required = "yes"
+		}
+		if _, err := fmt.Fprintf(w, "| `--%v` | %v | %v | %v | %v | %v |\n", doc.longName, shortName, markdownCell(doc.valueType), markdownCell(defaultValue), required, markdownCell(doc.help)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownCell escapes the pipes and line breaks that would end a Markdown table cell.
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(text)
+}
+
+// GenManFlags writes the flags of structType (a struct or pointer to struct) to w as the OPTIONS section of a man page in roff, for packaging pipelines that ship OS level documentation generated from the same struct as the CLI.
+func GenManFlags(structType reflect.Type, w io.Writer) error {
+	docs, err := structFlagDocs(structType)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, ".SH OPTIONS\n"); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		names := `\fB\-\-` + roffText(doc.longName) + `\fR`
+		if doc.shortName != "" {
+			names = `\fB\-` + roffText(doc.shortName) + `\fR, ` + names
+		}
+		if doc.takesValue {
+			names += `=\fI` + roffText(doc.valueType) + `\fR`
+		}
+		var notes []string
+		if doc.required {
+			notes = append(notes, "required")
+		}
+		if doc.defaultValue != "" {
+			notes = append(notes, "default: "+doc.defaultValue)
+		}
+		text := doc.help
+		if len(notes) > 0 {
+			text = strings.TrimSpace(text + " (" + strings.Join(notes, ", ") + ")")
+		}
+		if _, err := fmt.Fprintf(w, ".TP\n%v\n%v\n", names, roffLine(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roffText escapes the backslashes and hyphens of text for roff.
+func roffText(text string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+}
+
+// roffLine escapes text as a roff text line, so a leading dot or quote is not read as a request.
+func roffLine(text string) string {
+	text = roffText(strings.Join(strings.Fields(text), " "))
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		return `\&` + text
+	}
+	return text
+}
+
+// GenRSTFlags writes the flags of structType (a struct or pointer to struct) to w as a reStructuredText list-table with the columns of GenMarkdownFlags, for Sphinx and other docutils based documentation.
+func GenRSTFlags(structType reflect.Type, w io.Writer) error {
+	docs, err := structFlagDocs(structType)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, ".. list-table::\n   :header-rows: 1\n\n   * - Flag\n     - Short\n     - Type\n     - Default\n     - Required\n     - Description\n"); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		shortName, defaultValue, required := "", "", "no"
+		if doc.shortName != "" {
+			shortName = "``-" + doc.shortName + "``"
+		}
+		if doc.defaultValue != "" {
+			defaultValue = "``" + doc.defaultValue + "``"
+		}
+		if doc.required {
+			required = "yes"
+		}
+		cells := []string{"``--" + doc.longName + "``", shortName, rstText(doc.valueType), defaultValue, required, rstText(doc.help)}
+		for index, cell := range cells {
+			bullet := "     -"
+			if index == 0 {
+				bullet = "   * -"
+			}
+			if _, err := fmt.Fprintln(w, strings.TrimRight(bullet+" "+cell, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rstText escapes the inline markup characters of text for reStructuredText and keeps it on one line.
+func rstText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "|", `\|`, "_", `\_`).Replace(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,67 @@
+package cobraargs
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type docArgs struct {
+	Name    string `arg:"shortname=n,defaultvalue=bob" help:"the name | alias"`
+	Level   string `arg:"required" help:"log level, e.g. debug-verbose"`
+	Token   string `arg:"secret,defaultvalue=hunter2" help:"API token"`
+	Verbose int    `arg:"shortname=v,type=count" help:".dot leading help"`
+	Pattern string `arg:"placeholder=GLOB" help:"files like *_test.go\nor a \\path"`
+	Debug   bool
+}
+
+// checkGolden compares got with the golden file testdata/name, rewriting the file instead when the -update flag is given.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %v, got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestGenMarkdownFlags(t *testing.T) {
+	var out bytes.Buffer
+	if err := GenMarkdownFlags(reflect.TypeOf(&docArgs{}), &out); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "flags.md.golden", out.Bytes())
+	if err := GenMarkdownFlags(reflect.TypeOf(0), &out); err == nil {
+		t.Error("GenMarkdownFlags accepted a type that is not a struct")
+	}
+}
+
+func TestGenManFlags(t *testing.T) {
+	var out bytes.Buffer
+	if err := GenManFlags(reflect.TypeOf(docArgs{}), &out); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "flags.man.golden", out.Bytes())
+}
+
+func TestGenRSTFlags(t *testing.T) {
+	var out bytes.Buffer
+	if err := GenRSTFlags(reflect.TypeOf(docArgs{}), &out); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "flags.rst.golden", out.Bytes())
+}
@@ -0,0 +1,87 @@
+package cobraargs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadDotEnv reads KEY=VALUE lines from each dotenv file in paths, './.env' when none are given, into the process environment so the 'env' tag bindings see them. Variables already set in the environment win over the files, and earlier files win over later ones. A missing './.env' is ignored but a missing named path is an error. Call it before Execute.
+func LoadDotEnv(paths ...string) error {
+	optional := len(paths) == 0
+	if optional {
+		paths = []string{".env"}
+	}
+	for _, path := range paths {
+		values, err := readDotEnv(path)
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, item := range values {
+			if _, has := os.LookupEnv(item[0]); has {
+				continue
+			}
+			if err := os.Setenv(item[0], item[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readDotEnv parses a dotenv file into ordered name/value pairs. Blank lines and # comments are skipped, an 'export ' prefix is allowed, double quoted values are unquoted with Go escapes and single quoted values are taken literally.
+func readDotEnv(path string) ([][2]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var values [][2]string
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		nameValue := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(nameValue[0])
+		if len(nameValue) != 2 || name == "" {
+			return nil, fmt.Errorf("dotenv file %v line %v has no NAME=VALUE", path, lineNumber)
+		}
+		value, err := dotEnvValue(strings.TrimSpace(nameValue[1]))
+		if err != nil {
+			return nil, fmt.Errorf("dotenv file %v line %v: %v", path, lineNumber, err)
+		}
+		values = append(values, [2]string{name, value})
+	}
+	return values, scanner.Err()
+}
+
+func dotEnvValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.LastIndex(raw, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("value [%v] has no closing quote", raw)
+		}
+		return strconv.Unquote(raw[:end+1])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.LastIndex(raw, "'")
+		if end == 0 {
+			return "", fmt.Errorf("value [%v] has no closing quote", raw)
+		}
+		return raw[1:end], nil
+	}
+	if comment := strings.Index(raw, " #"); comment >= 0 {
+		raw = raw[:comment]
+	}
+	return strings.TrimSpace(raw), nil
+}
@@ -0,0 +1,73 @@
+package cobraargs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// envAnnotation is the flag annotation holding the environment variable names a flag reads when not set on the command line.
+const envAnnotation = "cobraargs_env"
+
+// envHookAnnotation marks a command whose PreRunE already applies environment variables.
+const envHookAnnotation = "cobraargs_env_hook"
+
+// ApplyEnvArgs sets every flag of cmd that was not given on the command line, and that has an 'env' tag, from the first of its environment variables that is set, so env=NEW_NAME|LEGACY_NAME prefers NEW_NAME. Flags still unset that have a 'fromdir' tag are then read from their directory. Attaching a flag with an 'env' or 'fromdir' tag installs a PreRunE hook calling it, so it only needs calling directly when that hook is replaced after attaching. A flag set this way reports Changed, so it satisfies 'required'.
+func ApplyEnvArgs(cmd *cobra.Command) error {
+	return ApplyFlagSetEnvArgs(cmd.Flags())
+}
+
+// ApplyFlagSetEnvArgs is ApplyEnvArgs for a flag set attached by AttachFlagSetArgs, called after flags.Parse since no command runs it.
+func ApplyFlagSetEnvArgs(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		for _, name := range flag.Annotations[envAnnotation] {
+			value, has := os.LookupEnv(name)
+			if !has {
+				continue
+			}
+			if setErr := setFlagFromSource(flags, flag, value, SourceEnv); setErr != nil {
+				if isSecretFlag(flag) {
+					setErr = secretSetError(flag)
+				}
+				err = fmt.Errorf("environment variable %v: %v", name, setErr)
+			}
+			return
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return applyDirArgs(flags)
+}
+
+// bindEnvArg records the 'env' variables of arg on its flag and shows them in the usage.
+func bindEnvArg(flags *pflag.FlagSet, arg Argument) error {
+	if len(arg.Env) == 0 || arg.Env[0] == "-" {
+		return nil
+	}
+	if err := flags.SetAnnotation(arg.LongName, envAnnotation, arg.Env); err != nil {
+		return err
+	}
+	flag := flags.Lookup(arg.LongName)
+	flag.Usage = fmt.Sprintf("%v [$%v]", strings.TrimSpace(flag.Usage), strings.Join(arg.Env, "|$"))
+	return nil
+}
+
+// installEnvHook makes sure cmd applies the 'env' and 'fromdir' tags of arg before running.
+func installEnvHook(cmd *cobra.Command, arg Argument) {
+	if (len(arg.Env) > 0 && arg.Env[0] != "-") || (arg.FromDir != "" && arg.FromDir != "-") {
+		installPreRunHook(cmd, envHookAnnotation, arg.Persistent, ApplyEnvArgs)
+	}
+}
+
+// envName derives the environment variable name for a long flag name, e.g. MYAPP and dbHost, db-host or dbHOSTName give MYAPP_DB_HOST and MYAPP_DB_HOST_NAME.
+func envName(prefix, longName string) string {
+	return strings.ToUpper(prefix + "_" + strings.Join(nameWords(longName), "_"))
+}
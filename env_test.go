@@ -0,0 +1,148 @@
+package cobraargs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// executeWithEnv attaches target to a new command with options, sets only the environment variables of env among names, and executes it with args.
+func executeWithEnv(t *testing.T, target interface{}, options Options, names []string, env map[string]string, args []string) (*cobra.Command, error) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+	cmd := &cobra.Command{Use: "db", Run: func(*cobra.Command, []string) {}}
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	if err := AttachStructArgsWithOptions(cmd, target, options); err != nil {
+		t.Fatal(err)
+	}
+	cmd.SetArgs(args)
+	return cmd, cmd.Execute()
+}
+
+func TestApplyEnvArgs(t *testing.T) {
+	type dbArgs struct {
+		Host string `arg:"env=TEST_ENV_HOST,defaultvalue=localhost"`
+		Port int    `arg:"env=TEST_ENV_PORT,defaultvalue=5432"`
+		User string `arg:"required,env=TEST_ENV_USER"`
+	}
+	names := []string{"TEST_ENV_HOST", "TEST_ENV_PORT", "TEST_ENV_USER"}
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		want       dbArgs
+		wantSource Source
+		wantErr    bool
+	}{
+		{name: "defaults", env: map[string]string{"TEST_ENV_USER": "app"}, want: dbArgs{Host: "localhost", Port: 5432, User: "app"}, wantSource: SourceDefault},
+		{name: "env", env: map[string]string{"TEST_ENV_USER": "app", "TEST_ENV_HOST": "db", "TEST_ENV_PORT": "6432"}, want: dbArgs{Host: "db", Port: 6432, User: "app"}, wantSource: SourceEnv},
+		{name: "flag over env", args: []string{"--host", "cli"}, env: map[string]string{"TEST_ENV_USER": "app", "TEST_ENV_HOST": "db"}, want: dbArgs{Host: "cli", Port: 5432, User: "app"}, wantSource: SourceFlag},
+		{name: "empty env value", env: map[string]string{"TEST_ENV_USER": "app", "TEST_ENV_HOST": ""}, want: dbArgs{Port: 5432, User: "app"}, wantSource: SourceEnv},
+		{name: "required missing", wantErr: true},
+		{name: "bad env value", env: map[string]string{"TEST_ENV_USER": "app", "TEST_ENV_PORT": "x"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args dbArgs
+			cmd, err := executeWithEnv(t, &args, Options{}, names, test.env, test.args)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Execute error %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if args != test.want {
+				t.Errorf("got %+v, want %+v", args, test.want)
+			}
+			if source := FlagSource(cmd, "host"); source != test.wantSource {
+				t.Errorf("FlagSource(host) = %v, want %v", source, test.wantSource)
+			}
+		})
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	type serverArgs struct {
+		MaxConns int    `arg:"defaultvalue=10"`
+		Host     string `arg:"env=TEST_PREFIX_OWN_HOST"`
+		Token    string `arg:"env=-"`
+	}
+	names := []string{"TEST_PREFIX_MAX_CONNS", "TEST_PREFIX_OWN_HOST", "TEST_PREFIX_HOST", "TEST_PREFIX_TOKEN"}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want serverArgs
+	}{
+		{name: "unset", want: serverArgs{MaxConns: 10}},
+		{name: "derived name", env: map[string]string{"TEST_PREFIX_MAX_CONNS": "20"}, want: serverArgs{MaxConns: 20}},
+		{name: "env tag kept", env: map[string]string{"TEST_PREFIX_OWN_HOST": "own", "TEST_PREFIX_HOST": "derived"}, want: serverArgs{MaxConns: 10, Host: "own"}},
+		{name: "opted out", env: map[string]string{"TEST_PREFIX_TOKEN": "t"}, want: serverArgs{MaxConns: 10}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args serverArgs
+			if _, err := executeWithEnv(t, &args, Options{EnvPrefix: "TEST_PREFIX"}, names, test.env, nil); err != nil {
+				t.Fatal(err)
+			}
+			if args != test.want {
+				t.Errorf("got %+v, want %+v", args, test.want)
+			}
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		longName string
+		want     string
+	}{
+		{prefix: "MYAPP", longName: "dbHost", want: "MYAPP_DB_HOST"},
+		{prefix: "MYAPP", longName: "db-host", want: "MYAPP_DB_HOST"},
+		{prefix: "MYAPP", longName: "db_host", want: "MYAPP_DB_HOST"},
+		{prefix: "MYAPP", longName: "dbHOSTName", want: "MYAPP_DB_HOST_NAME"},
+		{prefix: "MYAPP", longName: "port", want: "MYAPP_PORT"},
+	}
+	for _, test := range tests {
+		t.Run(test.longName, func(t *testing.T) {
+			if got := envName(test.prefix, test.longName); got != test.want {
+				t.Errorf("envName(%q, %q) = %q, want %q", test.prefix, test.longName, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEnvFallbacks(t *testing.T) {
+	type tokenArgs struct {
+		Token string `arg:"env=TEST_FALLBACK_NEW|TEST_FALLBACK_OLD|TEST_FALLBACK_OLDEST"`
+	}
+	names := []string{"TEST_FALLBACK_NEW", "TEST_FALLBACK_OLD", "TEST_FALLBACK_OLDEST"}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "none set"},
+		{name: "first", env: map[string]string{"TEST_FALLBACK_NEW": "new", "TEST_FALLBACK_OLD": "old"}, want: "new"},
+		{name: "second", env: map[string]string{"TEST_FALLBACK_OLD": "old", "TEST_FALLBACK_OLDEST": "oldest"}, want: "old"},
+		{name: "last", env: map[string]string{"TEST_FALLBACK_OLDEST": "oldest"}, want: "oldest"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args tokenArgs
+			if _, err := executeWithEnv(t, &args, Options{}, names, test.env, nil); err != nil {
+				t.Fatal(err)
+			}
+			if args.Token != test.want {
+				t.Errorf("got %q, want %q", args.Token, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Sentinel errors wrapped by ArgError. Use errors.Is to test for them.
+var (
+	ErrInvalidFieldName = errors.New("invalid field name")
+	ErrInvalidTagSyntax = errors.New("invalid arg tag syntax")
+	ErrInvalidTagValue  = errors.New("invalid arg tag value")
+	ErrUnknownTagKey    = errors.New("unknown arg tag key")
+	ErrShortNameTooLong = errors.New("short name is longer than one character")
+	ErrBadDefaultValue  = errors.New("bad default value")
+	ErrDuplicateName    = errors.New("duplicate flag name")
+	ErrUnsupportedType  = errors.New("unsupported field type")
+	ErrUnknownField     = errors.New("unknown field")
+)
+
+// ArgError describes a misconfigured argument field. Err is one of the sentinel errors; Struct, Field, TagKey and Value locate the problem when known.
+type ArgError struct {
+	Struct  string
+	Field   string
+	TagKey  string
+	Value   string
+	Err     error
+	message string
+}
+
+func (e *ArgError) Error() string {
+	return e.message
+}
+
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+func newArgError(err error, parmType reflect.Type, fieldName, tagKey, value, format string, a ...interface{}) *ArgError {
+	argError := &ArgError{Field: fieldName, TagKey: tagKey, Value: value, Err: err, message: fmt.Sprintf(format, a...)}
+	if parmType != nil {
+		argError.Struct = parmType.Name()
+	}
+	return argError
+}
+
+func badDefaultValueError(parmType reflect.Type, variableName string, arg Argument) *ArgError {
+	return newArgError(ErrBadDefaultValue, parmType, variableName, "defaultvalue", arg.DefaultValue, "field %v.%v could not process default value: %v", parmType.Name(), variableName, arg.DefaultValue)
+}
+
+// inStruct records the struct name on an ArgError raised where only the field was known. It annotates a copy, as err may be shared through argCache or fieldArgCache.
+func inStruct(err error, parmType reflect.Type) error {
+	argError, ok := err.(*ArgError)
+	if !ok || argError.Struct != "" {
+		return err
+	}
+	annotated := *argError
+	annotated.Struct = parmType.Name()
+	return &annotated
+}
@@ -0,0 +1,42 @@
+package cobraargs_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/doug4j/cobraargs"
+	"github.com/spf13/cobra"
+)
+
+// Execute returns ErrConfigPrinted once --print-config has printed the config, which main should treat as success rather than exit with status 1.
+func ExampleAttachPrintConfigFlag() {
+	type serverArgs struct {
+		Port int `arg:"defaultvalue=8080"`
+	}
+	var args serverArgs
+	cmd := &cobra.Command{Use: "server", Run: func(*cobra.Command, []string) {
+		fmt.Println("serving on", args.Port)
+	}}
+	if err := cobraargs.AttachStructArgs(cmd, &args); err != nil {
+		panic(err)
+	}
+	if err := cobraargs.AttachPrintConfigFlag(cmd); err != nil {
+		panic(err)
+	}
+	cmd.SetOut(os.Stdout)
+	cmd.SetArgs([]string{"--print-config=json", "--port", "9090"})
+	status := 0
+	if err := cmd.Execute(); err != nil && !errors.Is(err, cobraargs.ErrConfigPrinted) {
+		status = 1
+	}
+	fmt.Println("exit status", status) // os.Exit(status) in main
+	// Output:
+	// {
+	//   "port": {
+	//     "value": "9090",
+	//     "source": "flag"
+	//   }
+	// }
+	// exit status 0
+}
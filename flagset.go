@@ -0,0 +1,118 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// flagSetOwner names a flag set attached to without a command in errors.
+const flagSetOwner = "the flag set"
+
+// AttachFlagSetArgs is AttachStructArgs for programs not built on cobra, or for flag sets of their own such as one per subsystem: it binds a flag in flags to every arg field, as AttachStructArgs finds them, of the struct pointed to by target. The tags describing a single flag apply as they do on a command: its names, type, default, 'oneof', 'placeholder', 'nooptdefault', 'indirect', 'secret', 'aliases', 'negatable', 'hidden' and deprecations. 'env' and 'fromdir' are read by calling ApplyFlagSetEnvArgs after flags.Parse, and 'required' is recorded as cobra records it. The tags acting when a command runs, such as the validations, completions and flag groups, and a StructValidator, take effect only through AttachStructArgs, and positional fields are reported as errors.
+func AttachFlagSetArgs(flags *pflag.FlagSet, target interface{}) error {
+	return attachFlagSetArgs(flags, target, Options{})
+}
+
+// AttachFlagSetArgsWithOptions is AttachFlagSetArgs reading the tags named by options.
+func AttachFlagSetArgsWithOptions(flags *pflag.FlagSet, target interface{}, options Options) error {
+	return attachFlagSetArgs(flags, target, options)
+}
+
+func attachFlagSetArgs(flags *pflag.FlagSet, target interface{}, options Options) error {
+	structValue, err := structPointerValue(target)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	longNames := map[string]string{}
+	shortNames := map[string]string{}
+	structType := structValue.Type()
+	if options.NormalizeNames {
+		flags.SetNormalizeFunc(NormalizeFunc(options.NameStyle))
+	}
+	fields, err := structArgs(structType, options)
+	if err != nil {
+		return err
+	}
+	var autoShortNames map[string]string
+	if options.AutoShortNames {
+		taken := func(shortName string) bool { return flags.ShorthandLookup(shortName) != nil }
+		if autoShortNames, err = assignShortNames(taken, flagSetOwner, structType, fields, options); err != nil {
+			return err
+		}
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			errs = append(errs, inStruct(err, field.parent))
+			continue
+		}
+		if arg.Positional {
+			errs = append(errs, newArgError(ErrInvalidTagValue, field.parent, field.Name, "positional", "true", "field %v.%v is 'positional' which needs a command, not a flag set", field.parent.Name(), field.Name))
+			continue
+		}
+		if arg.ShortName == "" {
+			arg.ShortName = autoShortNames[field.path]
+		}
+		if err = checkDuplicateFlag([]*pflag.FlagSet{flags}, flagSetOwner, field.parent, field.Name, arg, longNames, shortNames); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(arg.Env) == 0 && options.EnvPrefix != "" {
+			arg.Env = []string{envName(options.EnvPrefix, arg.LongName)}
+		}
+		if arg.FromDir == "" {
+			arg.FromDir = options.FromDir
+		}
+		variableValue := structValue.FieldByIndex(field.Index).Addr().Interface()
+		if err = bindFieldArg(flags, field.parent, field.Name, arg, field.Tag.Get(options.helpTagKey()), variableValue); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = configureFlagSetArg(flags, flagSetOwner, arg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = flags.SetAnnotation(arg.LongName, fieldAnnotation, []string{field.parent.Name() + "." + field.Name}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnmarshalFlagSet populates the arg fields of the struct pointed to by target from the values of the flags parsed by flags, as Unmarshal does from a command.
+func UnmarshalFlagSet(flags *pflag.FlagSet, target interface{}) error {
+	return unmarshalFlagSet(flags, target, Options{})
+}
+
+// UnmarshalFlagSetWithOptions is UnmarshalFlagSet reading the tags named by options.
+func UnmarshalFlagSetWithOptions(flags *pflag.FlagSet, target interface{}, options Options) error {
+	return unmarshalFlagSet(flags, target, options)
+}
+
+func unmarshalFlagSet(flags *pflag.FlagSet, target interface{}, options Options) error {
+	structValue, err := structPointerValue(target)
+	if err != nil {
+		return err
+	}
+	fields, err := structArgs(structValue.Type(), options)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			return inStruct(err, field.parent)
+		}
+		if flags.Lookup(arg.LongName) == nil {
+			return fmt.Errorf("field %v.%v has no flag named [%v] in the flag set", field.parent.Name(), field.Name, arg.LongName)
+		}
+		variableValue := structValue.FieldByIndex(field.Index).Addr().Interface()
+		if err = unmarshalFieldArg(flags, field.parent, field.Name, arg, variableValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cobraargs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestAttachFlagSetArgs(t *testing.T) {
+	type serverArgs struct {
+		Host    string `arg:"shortname=h,defaultvalue=localhost,env=TEST_FLAGSET_HOST"`
+		Port    int    `arg:"defaultvalue=80,aliases=listen-port"`
+		Debug   bool   `arg:"negatable,defaultvalue=true"`
+		Workers int
+	}
+	type positionalArgs struct {
+		File string `arg:"positional"`
+	}
+	tests := []struct {
+		name    string
+		target  interface{}
+		args    []string
+		env     string
+		want    serverArgs
+		wantErr error
+	}{
+		{name: "defaults", target: &serverArgs{}, want: serverArgs{Host: "localhost", Port: 80, Debug: true}},
+		{name: "flags", target: &serverArgs{}, args: []string{"-h", "example.com", "--listen-port", "8080", "--no-debug", "--workers", "3"}, want: serverArgs{Host: "example.com", Port: 8080, Workers: 3}},
+		{name: "env", target: &serverArgs{}, env: "from-env", want: serverArgs{Host: "from-env", Port: 80, Debug: true}},
+		{name: "flag over env", target: &serverArgs{}, args: []string{"-h", "cli"}, env: "from-env", want: serverArgs{Host: "cli", Port: 80, Debug: true}},
+		{name: "positional", target: &positionalArgs{}, wantErr: ErrInvalidTagValue},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.env != "" {
+				t.Setenv("TEST_FLAGSET_HOST", test.env)
+			}
+			flags := pflag.NewFlagSet("server", pflag.ContinueOnError)
+			err := AttachFlagSetArgs(flags, test.target)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("AttachFlagSetArgs error %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := flags.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := ApplyFlagSetEnvArgs(flags); err != nil {
+				t.Fatal(err)
+			}
+			if got := *test.target.(*serverArgs); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			var unmarshaled serverArgs
+			if err := UnmarshalFlagSet(flags, &unmarshaled); err != nil {
+				t.Fatal(err)
+			}
+			if unmarshaled != test.want {
+				t.Errorf("UnmarshalFlagSet got %+v, want %+v", unmarshaled, test.want)
+			}
+		})
+	}
+}
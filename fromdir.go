@@ -0,0 +1,50 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// fromDirAnnotation is the flag annotation holding the 'fromdir' directory a flag reads a file named after its long name from.
+const fromDirAnnotation = "cobraargs_fromdir"
+
+// applyDirArgs sets every flag in flags not yet set that has a 'fromdir' directory from the file named after its long name in that directory, the layout of a mounted Kubernetes ConfigMap or Secret. A missing file leaves the flag alone and a trailing newline is dropped.
+func applyDirArgs(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		dirs := flag.Annotations[fromDirAnnotation]
+		if err != nil || flag.Changed || len(dirs) == 0 {
+			return
+		}
+		path := filepath.Join(dirs[0], flag.Name)
+		content, readErr := os.ReadFile(path)
+		if errors.Is(readErr, fs.ErrNotExist) {
+			return
+		}
+		if readErr != nil {
+			err = readErr
+			return
+		}
+		if setErr := setFlagFromSource(flags, flag, strings.TrimRight(string(content), "\r\n"), SourceDir); setErr != nil {
+			if isSecretFlag(flag) {
+				setErr = secretSetError(flag)
+			}
+			err = fmt.Errorf("file %v: %v", path, setErr)
+		}
+	})
+	return err
+}
+
+// bindDirArg records the 'fromdir' directory of arg on its flag.
+func bindDirArg(flags *pflag.FlagSet, arg Argument) error {
+	if arg.FromDir == "" || arg.FromDir == "-" {
+		return nil
+	}
+	return flags.SetAnnotation(arg.LongName, fromDirAnnotation, []string{arg.FromDir})
+}
@@ -0,0 +1,62 @@
+package cobraargs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyDirArgs(t *testing.T) {
+	type secretArgs struct {
+		Password string
+		Port     int    `arg:"defaultvalue=80"`
+		User     string `arg:"env=TEST_FROMDIR_USER"`
+		Skipped  string `arg:"fromdir=-"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		files   map[string]string
+		want    secretArgs
+		wantErr bool
+	}{
+		{name: "no files", want: secretArgs{Port: 80}},
+		{name: "files", files: map[string]string{"password": "s3cret\n", "port": "8080"}, want: secretArgs{Password: "s3cret", Port: 8080}},
+		{name: "trailing newlines dropped", files: map[string]string{"password": "line\r\n\n"}, want: secretArgs{Password: "line", Port: 80}},
+		{name: "opted out", files: map[string]string{"skipped": "file"}, want: secretArgs{Port: 80}},
+		{name: "flag over file", args: []string{"--password", "cli"}, files: map[string]string{"password": "file"}, want: secretArgs{Password: "cli", Port: 80}},
+		{name: "env over file", env: "env-user", files: map[string]string{"user": "file-user"}, want: secretArgs{Port: 80, User: "env-user"}},
+		{name: "bad file value", files: map[string]string{"port": "http"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("TEST_FROMDIR_USER", test.env)
+			if test.env == "" {
+				os.Unsetenv("TEST_FROMDIR_USER")
+			}
+			dir := t.TempDir()
+			for name, content := range test.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var args secretArgs
+			cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+			cmd.SilenceErrors, cmd.SilenceUsage = true, true
+			if err := AttachStructArgsWithOptions(cmd, &args, Options{FromDir: dir}); err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Execute error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && args != test.want {
+				t.Errorf("got %+v, want %+v", args, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cobraargs
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// SupportedFlagType lists the field types AttachArg can bind. Field types implementing pflag.Value or encoding.TextUnmarshaler are supported by AttachStructArgs but cannot be expressed in this constraint.
+type SupportedFlagType interface {
+	string | bool |
+		int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 |
+		time.Duration | time.Time | os.FileMode |
+		net.IP | net.IPNet | *url.URL |
+		[]byte | json.RawMessage |
+		[]string | []int | []int64 | []float32 | []float64 | []time.Duration | []net.IP |
+		map[string]string | map[string]int
+}
+
+// AttachArg uses reflection to read the provided struct to determine the arguments. The flag type is inferred from T the same way AttachStructArgs infers it from the field type.
+func AttachArg[T SupportedFlagType](cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *T) {
+	mustAttach(AttachArgE(cmd, parmType, variableName, variableValue))
+}
+
+// AttachArgE is AttachArg returning an error instead of panicking.
+func AttachArgE[T SupportedFlagType](cmd *cobra.Command, parmType reflect.Type, variableName string, variableValue *T) error {
+	return attachTypedArgE(cmd, parmType, variableName, variableValue)
+}
+
+// Bind attaches the flags of the params struct T to cmd and sets RunE to call handler with the command context and a fresh T populated from the parsed flags and arguments, as by Unmarshal.
+func Bind[T any](cmd *cobra.Command, handler func(ctx context.Context, params *T) error) {
+	mustAttach(BindE(cmd, handler))
+}
+
+// BindE is Bind returning an error instead of panicking.
+func BindE[T any](cmd *cobra.Command, handler func(ctx context.Context, params *T) error) error {
+	if err := AttachStructArgs(cmd, new(T)); err != nil {
+		return err
+	}
+	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+		params := new(T)
+		if err := Unmarshal(cmd, params); err != nil {
+			return err
+		}
+		return handler(cmd.Context(), params)
+	}
+	cmd.Run = nil
+	return nil
+}
@@ -1,5 +1,12 @@
 module github.com/doug4j/cobraargs
 
-go 1.13
+go 1.20
 
-require github.com/spf13/cobra v0.0.5
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/spf13/cobra v1.8.1
+	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
@@ -0,0 +1,44 @@
+package cobraargs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// flagGroups collects, per group name, the long names of the fields of one struct tagged with that group.
+type flagGroups map[string][]string
+
+func (groups flagGroups) add(names []string, longName string) {
+	for _, name := range names {
+		groups[name] = append(groups[name], longName)
+	}
+}
+
+// markFlagGroups passes every group of at least two flags to mark, the cobra call enforcing the group, and reports groups naming a single field since those are almost always a misspelled group name.
+func markFlagGroups(structName, tagName string, groups flagGroups, mark func(flagNames ...string)) error {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(groups[name]) < 2 {
+			return fmt.Errorf("struct %v has '%v' group [%v] on only the field for flag --%v", structName, tagName, name, groups[name][0])
+		}
+		mark(groups[name]...)
+	}
+	return nil
+}
+
+// markStructGroups applies the 'xor', 'together' and 'onerequired' groups collected while attaching a struct's fields to cmd.
+func markStructGroups(cmd *cobra.Command, structName string, xor, together, oneRequired flagGroups) error {
+	if err := markFlagGroups(structName, "xor", xor, cmd.MarkFlagsMutuallyExclusive); err != nil {
+		return err
+	}
+	if err := markFlagGroups(structName, "together", together, cmd.MarkFlagsRequiredTogether); err != nil {
+		return err
+	}
+	return markFlagGroups(structName, "onerequired", oneRequired, cmd.MarkFlagsOneRequired)
+}
@@ -0,0 +1,80 @@
+package cobraargs
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// testGroupAttach attaches target to a new command, expecting it to succeed when wantAttach, and reports the error of executing it with args.
+func testGroupAttach(t *testing.T, target interface{}, wantAttach bool, args []string) error {
+	t.Helper()
+	cmd := &cobra.Command{Use: "out", Run: func(*cobra.Command, []string) {}}
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	err := AttachStructArgs(cmd, target)
+	if (err == nil) != wantAttach {
+		t.Fatalf("AttachStructArgs error %v, want success %v", err, wantAttach)
+	}
+	if err != nil {
+		return nil
+	}
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func TestXorGroups(t *testing.T) {
+	type outputArgs struct {
+		Text bool `arg:"xor=format"`
+		Yaml bool `arg:"xor=format"`
+	}
+	type lonelyArgs struct {
+		Text bool `arg:"xor=fromat"`
+		Yaml bool `arg:"xor=format"`
+	}
+	tests := []struct {
+		name       string
+		target     interface{}
+		args       []string
+		wantAttach bool
+		wantErr    bool
+	}{
+		{name: "none", target: &outputArgs{}, wantAttach: true},
+		{name: "one", target: &outputArgs{}, args: []string{"--text"}, wantAttach: true},
+		{name: "both", target: &outputArgs{}, args: []string{"--text", "--yaml"}, wantAttach: true, wantErr: true},
+		{name: "group of one field", target: &lonelyArgs{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := testGroupAttach(t, test.target, test.wantAttach, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTogetherAndOneRequiredGroups(t *testing.T) {
+	type authArgs struct {
+		User     string `arg:"together=auth"`
+		Password string `arg:"together=auth"`
+		Token    string `arg:"onerequired=credential"`
+		Key      string `arg:"onerequired=credential"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "one required", args: []string{"--token", "t"}},
+		{name: "both of one required", args: []string{"--token", "t", "--key", "k"}},
+		{name: "none of one required", args: []string{}, wantErr: true},
+		{name: "together", args: []string{"--key", "k", "--user", "u", "--password", "p"}},
+		{name: "half of together", args: []string{"--key", "k", "--user", "u"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := testGroupAttach(t, &authArgs{}, true, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package cobraargs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// sourceAnnotation is the flag annotation recording which Source set a flag not given on the command line.
+const sourceAnnotation = "cobraargs_source"
+
+// Source names where the value of an attached flag came from.
+type Source string
+
+const (
+	// SourceFlag is a value given on the command line.
+	SourceFlag Source = "flag"
+	// SourceEnv is a value read from an 'env' environment variable.
+	SourceEnv Source = "env"
+	// SourceDir is a value read from a file in a 'fromdir' directory.
+	SourceDir Source = "dir"
+	// SourceConfig is a value read from the config file.
+	SourceConfig Source = "config"
+	// SourceProfile is a value read from the profile picked with --profile.
+	SourceProfile Source = "profile"
+	// SourceDefault is the tag default value, or the zero value when there is none.
+	SourceDefault Source = "default"
+)
+
+// FlagSource reports where the current value of the named flag of cmd came from.
+func FlagSource(cmd *cobra.Command, name string) Source {
+	flag := cmd.Flag(name)
+	if flag == nil || !flag.Changed {
+		return SourceDefault
+	}
+	if sources := flag.Annotations[sourceAnnotation]; len(sources) > 0 {
+		return Source(sources[0])
+	}
+	return SourceFlag
+}
+
+// setFlagFromSource sets a flag not given on the command line and records source as where its value came from.
+func setFlagFromSource(flags *pflag.FlagSet, flag *pflag.Flag, value string, source Source) error {
+	if err := flags.Set(flag.Name, value); err != nil {
+		return err
+	}
+	return flags.SetAnnotation(flag.Name, sourceAnnotation, []string{string(source)})
+}
+
+// installPreRunHook wraps the PreRunE of cmd, or for persistent flags its PersistentPreRunE so subcommands run it too, to call apply first. Each key is installed once per command and an existing PreRun or PersistentPreRun is kept by the wrapper. The persistent hooks installed last run first.
+func installPreRunHook(cmd *cobra.Command, key string, persistent bool, apply func(*cobra.Command) error) {
+	if persistent {
+		key += "_persistent"
+	}
+	if cmd.Annotations[key] != "" {
+		return
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[key] = "true"
+	if persistent {
+		installPersistentHook(cmd, func(hooks *persistentHooks) {
+			hooks.pre = append([]func(*cobra.Command) error{apply}, hooks.pre...)
+		})
+		return
+	}
+	cmd.PreRunE = withPreRunHook(apply, cmd.PreRunE, cmd.PreRun)
+	cmd.PreRun = nil
+}
+
+// Stages order the hooks run after the PersistentPreRunE steps, such as the config file, so later stages see the values earlier ones set.
+const (
+	stageProfile = iota
+	stagePrintConfig
+	stageValidate
+)
+
+type postPreRunHook struct {
+	stage int
+	apply func(*cobra.Command) error
+}
+
+// persistentHooks are the hooks the PersistentPreRunE of a command runs: pre before the PersistentPreRunE or PersistentPreRun it replaced, next, and post after it in stage order.
+type persistentHooks struct {
+	pre  []func(*cobra.Command) error
+	next func(*cobra.Command, []string) error
+	post []postPreRunHook
+}
+
+var (
+	persistentHooksLock sync.Mutex
+	persistentHooksOf   = map[*cobra.Command]*persistentHooks{}
+)
+
+// installPostPreRunHook makes the PersistentPreRunE of cmd call apply, in stage order with the other post hooks, after whatever it already ran.
+func installPostPreRunHook(cmd *cobra.Command, stage int, apply func(*cobra.Command) error) {
+	installPersistentHook(cmd, func(hooks *persistentHooks) {
+		hooks.post = append(hooks.post, postPreRunHook{stage: stage, apply: apply})
+		sort.SliceStable(hooks.post, func(i, j int) bool { return hooks.post[i].stage < hooks.post[j].stage })
+	})
+}
+
+// installPersistentHook applies add to the persistent hooks of cmd, replacing its PersistentPreRunE by runPersistentHooks the first time. Cobra only runs the PersistentPreRunE nearest the command executed, so unless cobra.EnableTraverseRunHooks is set runPersistentHooks also runs the hooks installed on the ancestors of cmd, such as the config file loader of the root: a subcommand with a PersistentPreRun of its own set after attaching needs cobra.EnableTraverseRunHooks for them to run.
+func installPersistentHook(cmd *cobra.Command, add func(*persistentHooks)) {
+	persistentHooksLock.Lock()
+	defer persistentHooksLock.Unlock()
+	hooks, installed := persistentHooksOf[cmd]
+	if !installed {
+		hooks = &persistentHooks{next: withPreRunHook(func(*cobra.Command) error { return nil }, cmd.PersistentPreRunE, cmd.PersistentPreRun)}
+		persistentHooksOf[cmd] = hooks
+		owner := cmd
+		cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+			return runPersistentHooks(owner, cmd, args)
+		}
+		cmd.PersistentPreRun = nil
+	}
+	add(hooks)
+}
+
+// runPersistentHooks runs the persistent hooks of owner for the executed cmd, with those of the ancestors of owner unless cobra runs their PersistentPreRunE itself. The pre hooks run from the root down, then the PersistentPreRunE owner had, then the post hooks of them all in stage order.
+func runPersistentHooks(owner, cmd *cobra.Command, args []string) error {
+	chain := []*cobra.Command{owner}
+	if !cobra.EnableTraverseRunHooks {
+		for parent := owner.Parent(); parent != nil; parent = parent.Parent() {
+			chain = append([]*cobra.Command{parent}, chain...)
+		}
+	}
+	var pre []func(*cobra.Command) error
+	var post []postPreRunHook
+	persistentHooksLock.Lock()
+	for _, ancestor := range chain {
+		if hooks, has := persistentHooksOf[ancestor]; has {
+			pre = append(pre, hooks.pre...)
+			post = append(post, hooks.post...)
+		}
+	}
+	next := persistentHooksOf[owner].next
+	persistentHooksLock.Unlock()
+	sort.SliceStable(post, func(i, j int) bool { return post[i].stage < post[j].stage })
+	for _, apply := range pre {
+		if err := apply(cmd); err != nil {
+			return err
+		}
+	}
+	if err := next(cmd, args); err != nil {
+		return err
+	}
+	for _, hook := range post {
+		if err := hook.apply(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func withPreRunHook(apply func(*cobra.Command) error, next func(*cobra.Command, []string) error, nextRun func(*cobra.Command, []string)) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := apply(cmd); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(cmd, args)
+		}
+		if nextRun != nil {
+			nextRun(cmd, args)
+		}
+		return nil
+	}
+}
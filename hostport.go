@@ -0,0 +1,78 @@
+package cobraargs
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostPort is a network address such as 'localhost:8080', '[::1]:443' or ':9090', parsed into its host and port. Fields of this type bind as 'hostport' flags; string fields do with the 'type=hostport' tag.
+type HostPort struct {
+	Host string
+	Port int
+}
+
+// ParseHostPort splits a host:port address, requiring a port between 1 and 65535. The host may be empty, meaning every interface.
+func ParseHostPort(address string) (HostPort, error) {
+	host, rawPort, err := net.SplitHostPort(strings.TrimSpace(address))
+	if err != nil {
+		return HostPort{}, fmt.Errorf("'%v' is not a host:port address: %w", address, err)
+	}
+	port, err := strconv.Atoi(rawPort)
+	if err != nil || port < 1 || port > 65535 {
+		return HostPort{}, fmt.Errorf("'%v' has port '%v' which is not between 1 and 65535", address, rawPort)
+	}
+	return HostPort{Host: host, Port: port}, nil
+}
+
+// String joins the host and port, bracketing IPv6 hosts. The zero HostPort is the empty string.
+func (h HostPort) String() string {
+	if h == (HostPort{}) {
+		return ""
+	}
+	return net.JoinHostPort(h.Host, strconv.Itoa(h.Port))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (h HostPort) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseHostPort.
+func (h *HostPort) UnmarshalText(text []byte) error {
+	parsed, err := ParseHostPort(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// hostPortValue is a pflag.Value for a string field holding a validated host:port address.
+type hostPortValue struct {
+	value *string
+}
+
+func newHostPortValue(value *string) *hostPortValue {
+	return &hostPortValue{value: value}
+}
+
+func (h *hostPortValue) Set(val string) error {
+	if _, err := ParseHostPort(val); err != nil {
+		return err
+	}
+	*h.value = strings.TrimSpace(val)
+	return nil
+}
+
+func (h *hostPortValue) String() string {
+	if h.value == nil {
+		return ""
+	}
+	return *h.value
+}
+
+func (h *hostPortValue) Type() string {
+	return "hostport"
+}
@@ -0,0 +1,77 @@
+package cobraargs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// indirectSchemes are the value prefixes an 'indirect' tag may allow. A bare 'indirect' allows file and env only, exec must be named.
+var indirectSchemes = []string{"file", "env", "exec"}
+
+// indirectValue wraps a pflag.Value, resolving values of the form file:/run/secrets/token, env:TOKEN, exec:command args or <scheme>:reference for a registered Resolver before setting them.
+type indirectValue struct {
+	pflag.Value
+	schemes []string
+}
+
+func (value *indirectValue) Set(raw string) error {
+	resolved, err := resolveIndirect(raw, value.schemes)
+	if err != nil {
+		return err
+	}
+	return value.Value.Set(resolved)
+}
+
+func (value *indirectValue) unwrap() pflag.Value {
+	return value.Value
+}
+
+// resolveIndirect returns the value raw refers to when it starts with one of the allowed schemes, and raw itself otherwise. Trailing newlines of file contents and command output are dropped.
+func resolveIndirect(raw string, schemes []string) (string, error) {
+	scheme, reference, ok := strings.Cut(raw, ":")
+	if !ok || !containsString(schemes, scheme) {
+		return raw, nil
+	}
+	switch scheme {
+	case "file":
+		content, err := os.ReadFile(reference)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case "env":
+		value, has := os.LookupEnv(reference)
+		if !has {
+			return "", fmt.Errorf("environment variable %v is not set", reference)
+		}
+		return value, nil
+	case "exec":
+		command := strings.Fields(reference)
+		if len(command) == 0 {
+			return "", fmt.Errorf("exec: has no command")
+		}
+		output, err := exec.Command(command[0], command[1:]...).Output()
+		if err != nil {
+			return "", fmt.Errorf("exec:%v failed: %v", command[0], err)
+		}
+		return strings.TrimRight(string(output), "\r\n"), nil
+	}
+	if resolver, has := lookupResolver(scheme); has {
+		return resolver.Resolve(context.Background(), reference)
+	}
+	return raw, nil
+}
+
+// attachIndirectArg makes the flag of an 'indirect' arg resolve file:, env: and exec: references whether its value comes from the command line, the environment or a config file.
+func attachIndirectArg(flags *pflag.FlagSet, arg Argument) {
+	if len(arg.Indirect) == 0 {
+		return
+	}
+	flag := flags.Lookup(arg.LongName)
+	flag.Value = &indirectValue{Value: flag.Value, schemes: arg.Indirect}
+}
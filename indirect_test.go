@@ -0,0 +1,54 @@
+package cobraargs
+
+import (
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestIndirectUnmarshal(t *testing.T) {
+	type indirectArgs struct {
+		Addr  string      `arg:"type=hostport,indirect"`
+		Size  int64       `arg:"type=bytesize,indirect"`
+		Mode  os.FileMode `arg:"indirect"`
+		When  time.Time   `arg:"layout=2006-01-02,indirect"`
+		Site  *url.URL    `arg:"indirect"`
+		Zone  string      `arg:"type=hostport,oneof=a:1|b:2"`
+		Limit int64       `arg:"type=bytesize,oneof=1KB|1MB"`
+	}
+	t.Setenv("TEST_INDIRECT_ADDR", "localhost:8080")
+	t.Setenv("TEST_INDIRECT_SIZE", "2KiB")
+	t.Setenv("TEST_INDIRECT_MODE", "0640")
+	t.Setenv("TEST_INDIRECT_WHEN", "2024-02-03")
+	t.Setenv("TEST_INDIRECT_SITE", "https://example.com/a")
+	var args indirectArgs
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := AttachStructArgs(cmd, &args); err != nil {
+		t.Fatal(err)
+	}
+	cmd.SetArgs([]string{
+		"--addr", "env:TEST_INDIRECT_ADDR", "--size", "env:TEST_INDIRECT_SIZE", "--mode", "env:TEST_INDIRECT_MODE",
+		"--when", "env:TEST_INDIRECT_WHEN", "--site", "env:TEST_INDIRECT_SITE", "--zone", "b:2", "--limit", "1MB",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	want := indirectArgs{
+		Addr: "localhost:8080", Size: 2048, Mode: 0o640, When: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		Site: &url.URL{Scheme: "https", Host: "example.com", Path: "/a"}, Zone: "b:2", Limit: 1000000,
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("attached %+v, want %+v", args, want)
+	}
+	var unmarshaled indirectArgs
+	if err := Unmarshal(cmd, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unmarshaled, want) {
+		t.Errorf("Unmarshal got %+v, want %+v", unmarshaled, want)
+	}
+}
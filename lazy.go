@@ -0,0 +1,88 @@
+package cobraargs
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// lazyAttach is a struct recorded by AttachStructArgsLazy, attached when its command is materialized.
+type lazyAttach struct {
+	target  interface{}
+	options Options
+}
+
+var (
+	lazyAttachesLock sync.Mutex
+	lazyAttaches     = map[*cobra.Command][]lazyAttach{}
+)
+
+// AttachStructArgsLazy records target to be attached to cmd, as AttachStructArgs attaches it, only once MaterializeArgs, or ExecuteLazy, finds that cmd or one of its subcommands is the command run. A CLI with hundreds of subcommands then only reflects over the structs, and creates the flags and validation hooks, of the command line given. Tag errors are only reported then, so check the structs in tests with ValidateStruct.
+func AttachStructArgsLazy(cmd *cobra.Command, target interface{}) error {
+	return attachStructArgsLazy(cmd, target, Options{})
+}
+
+// AttachStructArgsLazyWithOptions is AttachStructArgsLazy reading the tags named by options.
+func AttachStructArgsLazyWithOptions(cmd *cobra.Command, target interface{}, options Options) error {
+	return attachStructArgsLazy(cmd, target, options)
+}
+
+func attachStructArgsLazy(cmd *cobra.Command, target interface{}, options Options) error {
+	if _, err := structPointerValue(target); err != nil {
+		return err
+	}
+	lazyAttachesLock.Lock()
+	defer lazyAttachesLock.Unlock()
+	lazyAttaches[cmd] = append(lazyAttaches[cmd], lazyAttach{target: target, options: options})
+	return nil
+}
+
+// MaterializeArgs attaches the structs recorded by AttachStructArgsLazy for the command args resolve to under root and for its ancestors, whose persistent flags it inherits. The ancestors are attached first, so their flags are known while resolving the rest of args. For the help and shell completion commands, as in `help serve`, the command asked about is materialized. Call it with the arguments given to root.SetArgs, or use ExecuteLazy.
+func MaterializeArgs(root *cobra.Command, args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		args = args[1:]
+	}
+	var errs []error
+	for cmd, previous := root, (*cobra.Command)(nil); cmd != previous; {
+		for ancestor := cmd; ancestor != nil; ancestor = ancestor.Parent() {
+			errs = append(errs, attachLazyArgs(ancestor))
+		}
+		previous = cmd
+		if found, _, err := root.Find(args); err == nil {
+			cmd = found
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MaterializeAllArgs attaches every struct recorded by AttachStructArgsLazy for root and all its subcommands, for tools such as the documentation generators that need every flag of the tree.
+func MaterializeAllArgs(root *cobra.Command) error {
+	errs := []error{attachLazyArgs(root)}
+	for _, sub := range root.Commands() {
+		errs = append(errs, MaterializeAllArgs(sub))
+	}
+	return errors.Join(errs...)
+}
+
+// ExecuteLazy materializes the command given by the process arguments with MaterializeArgs, then executes root.
+func ExecuteLazy(root *cobra.Command) error {
+	if err := MaterializeArgs(root, os.Args[1:]); err != nil {
+		return err
+	}
+	return root.Execute()
+}
+
+// attachLazyArgs attaches, once, the structs recorded for cmd.
+func attachLazyArgs(cmd *cobra.Command) error {
+	lazyAttachesLock.Lock()
+	pending := lazyAttaches[cmd]
+	delete(lazyAttaches, cmd)
+	lazyAttachesLock.Unlock()
+	var errs []error
+	for _, attach := range pending {
+		errs = append(errs, attachStructArgs(cmd, attach.target, attach.options))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package cobraargs
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestMaterializeArgs(t *testing.T) {
+	type rootArgs struct {
+		Verbose bool `arg:"persistent,shortname=v"`
+	}
+	type serveArgs struct {
+		Port int `arg:"defaultvalue=8080"`
+	}
+	type buildArgs struct {
+		Output string `arg:"defaultvalue=bin"`
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		wantServe bool
+		wantBuild bool
+	}{
+		{name: "subcommand", args: []string{"serve", "--port", "1"}, wantServe: true},
+		{name: "persistent flag first", args: []string{"-v", "build"}, wantBuild: true},
+		{name: "help", args: []string{"help", "serve"}, wantServe: true},
+		{name: "root only", args: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "app"}
+			serve := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+			build := &cobra.Command{Use: "build", Run: func(*cobra.Command, []string) {}}
+			root.AddCommand(serve, build)
+			var rootTarget rootArgs
+			var serveTarget serveArgs
+			var buildTarget buildArgs
+			for cmd, target := range map[*cobra.Command]interface{}{root: &rootTarget, serve: &serveTarget, build: &buildTarget} {
+				if err := AttachStructArgsLazy(cmd, target); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := MaterializeArgs(root, test.args); err != nil {
+				t.Fatal(err)
+			}
+			if root.PersistentFlags().Lookup("verbose") == nil {
+				t.Error("root flags not attached")
+			}
+			if got := serve.Flags().Lookup("port") != nil; got != test.wantServe {
+				t.Errorf("serve attached %v, want %v", got, test.wantServe)
+			}
+			if got := build.Flags().Lookup("output") != nil; got != test.wantBuild {
+				t.Errorf("build attached %v, want %v", got, test.wantBuild)
+			}
+			if err := MaterializeAllArgs(root); err != nil {
+				t.Fatal(err)
+			}
+			if serve.Flags().Lookup("port") == nil || build.Flags().Lookup("output") == nil {
+				t.Error("MaterializeAllArgs left a command unattached")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cobraargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes a command, its flags, positional arguments and subcommands, for UI generators and audit tooling. Marshal it as JSON or YAML, or write it with WriteManifest.
+type Manifest struct {
+	Name       string         `json:"name" yaml:"name"`
+	Use        string         `json:"use" yaml:"use"`
+	Aliases    []string       `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Short      string         `json:"short,omitempty" yaml:"short,omitempty"`
+	Long       string         `json:"long,omitempty" yaml:"long,omitempty"`
+	Hidden     bool           `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Deprecated string         `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Flags      []ManifestFlag `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Args       []ManifestArg  `json:"args,omitempty" yaml:"args,omitempty"`
+	Commands   []Manifest     `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// ManifestFlag describes a flag declared by cmd, with the rules of its validation tags keyed by tag name, such as range with its min and max or xor with its groups.
+type ManifestFlag struct {
+	Name       string              `json:"name" yaml:"name"`
+	Shorthand  string              `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Type       string              `json:"type" yaml:"type"`
+	Default    string              `json:"default,omitempty" yaml:"default,omitempty"`
+	Usage      string              `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Field      string              `json:"field,omitempty" yaml:"field,omitempty"`
+	Required   bool                `json:"required,omitempty" yaml:"required,omitempty"`
+	Persistent bool                `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+	Hidden     bool                `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Deprecated string              `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Secret     bool                `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Env        []string            `json:"env,omitempty" yaml:"env,omitempty"`
+	Config     string              `json:"config,omitempty" yaml:"config,omitempty"`
+	Choices    []string            `json:"choices,omitempty" yaml:"choices,omitempty"`
+	Complete   string              `json:"complete,omitempty" yaml:"complete,omitempty"`
+	Rules      map[string][]string `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// ManifestArg describes a positional field of a command, Variadic for a 'pos=rest' or 'passthrough' field.
+type ManifestArg struct {
+	Name        string   `json:"name" yaml:"name"`
+	Type        string   `json:"type" yaml:"type"`
+	Default     string   `json:"default,omitempty" yaml:"default,omitempty"`
+	Usage       string   `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Required    bool     `json:"required,omitempty" yaml:"required,omitempty"`
+	Variadic    bool     `json:"variadic,omitempty" yaml:"variadic,omitempty"`
+	Passthrough bool     `json:"passthrough,omitempty" yaml:"passthrough,omitempty"`
+	Choices     []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+	Complete    string   `json:"complete,omitempty" yaml:"complete,omitempty"`
+}
+
+// flagGroupRules maps the annotations cobra records flag groups under to the tag keys declaring them.
+var flagGroupRules = map[string]string{
+	"cobra_annotation_mutually_exclusive":     "xor",
+	"cobra_annotation_required_if_others_set": "together",
+	"cobra_annotation_one_required":           "onerequired",
+}
+
+// ExportManifest describes cmd and its subcommands: every flag it declares with its type, default and validation rules, and its positional fields. The default of a 'secret' flag is given as RedactedValue.
+func ExportManifest(cmd *cobra.Command) Manifest {
+	manifest := Manifest{Name: cmd.Name(), Use: cmd.Use, Aliases: cmd.Aliases, Short: cmd.Short, Long: cmd.Long, Hidden: cmd.Hidden, Deprecated: cmd.Deprecated}
+	specFlags(cmd, func(flag *pflag.Flag, persistent bool) {
+		manifest.Flags = append(manifest.Flags, manifestFlag(flag, persistent))
+	})
+	if positional, has := lookupPositionalArgs(cmd); has {
+		for index, arg := range positional.args {
+			manifest.Args = append(manifest.Args, positional.manifestArg(arg, index < positional.required))
+		}
+		if positional.rest != nil {
+			manifestArg := positional.manifestArg(*positional.rest, positional.required > len(positional.args))
+			manifestArg.Variadic = true
+			manifest.Args = append(manifest.Args, manifestArg)
+		}
+		if positional.passthrough != nil {
+			manifestArg := positional.manifestArg(*positional.passthrough, false)
+			manifestArg.Variadic, manifestArg.Passthrough = true, true
+			manifest.Args = append(manifest.Args, manifestArg)
+		}
+	}
+	for _, subcommand := range cmd.Commands() {
+		manifest.Commands = append(manifest.Commands, ExportManifest(subcommand))
+	}
+	return manifest
+}
+
+func manifestFlag(flag *pflag.Flag, persistent bool) ManifestFlag {
+	manifestFlag := ManifestFlag{Name: flag.Name, Shorthand: flag.Shorthand, Type: flag.Value.Type(), Default: flag.DefValue, Usage: manifestUsage(flag.Usage), Required: flagRequired(flag), Persistent: persistent, Hidden: flag.Hidden, Deprecated: flag.Deprecated, Secret: isSecretFlag(flag), Env: flag.Annotations[envAnnotation]}
+	if manifestFlag.Secret && manifestFlag.Default != "" {
+		manifestFlag.Default = RedactedValue
+	}
+	if field := flag.Annotations[fieldAnnotation]; len(field) > 0 {
+		manifestFlag.Field = field[0]
+	}
+	if config := flag.Annotations[configAnnotation]; len(config) > 0 {
+		manifestFlag.Config = config[0]
+	}
+	if complete := flag.Annotations[completeAnnotation]; len(complete) > 0 {
+		manifestFlag.Complete = complete[0]
+	}
+	if oneOf := flagOneOf(flag); oneOf != nil {
+		manifestFlag.Choices = oneOf.choices
+	}
+	for _, flagCheck := range flagChecks {
+		if values, has := flag.Annotations[flagCheck.annotation]; has {
+			manifestFlag.addRule(strings.TrimPrefix(flagCheck.annotation, "cobraargs_"), values)
+		}
+	}
+	for annotation, rule := range flagGroupRules {
+		if groups, has := flag.Annotations[annotation]; has {
+			manifestFlag.addRule(rule, groups)
+		}
+	}
+	return manifestFlag
+}
+
+// manifestUsage returns the usage of a flag, empty for a field without help whose usage is only the "optional: " or "MANDATORY: " prefix.
+func manifestUsage(usage string) string {
+	if usage == "optional: " || usage == "MANDATORY: " {
+		return ""
+	}
+	return usage
+}
+
+func (manifestFlag *ManifestFlag) addRule(rule string, values []string) {
+	if manifestFlag.Rules == nil {
+		manifestFlag.Rules = map[string][]string{}
+	}
+	manifestFlag.Rules[rule] = values
+}
+
+func (positional *positionalArgs) manifestArg(arg Argument, required bool) ManifestArg {
+	flag := positional.holder.Flags().Lookup(arg.LongName)
+	manifestArg := ManifestArg{Name: positionalName(arg), Type: flag.Value.Type(), Default: flag.DefValue, Usage: manifestUsage(flag.Usage), Required: required, Choices: arg.OneOf, Complete: arg.Complete}
+	if isSecretFlag(flag) && manifestArg.Default != "" {
+		manifestArg.Default = RedactedValue
+	}
+	return manifestArg
+}
+
+// WriteManifest writes the ExportManifest of cmd to w as 'json' or 'yaml'.
+func WriteManifest(w io.Writer, cmd *cobra.Command, format string) error {
+	manifest := ExportManifest(cmd)
+	switch format {
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(manifest); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(manifest)
+	}
+	return fmt.Errorf("manifest format [%v] is not json or yaml", format)
+}
@@ -0,0 +1,51 @@
+package cobraargs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWriteManifest(t *testing.T) {
+	type rootArgs struct {
+		Verbose bool `arg:"persistent,shortname=v" help:"more output"`
+	}
+	type deployArgs struct {
+		Region  string   `arg:"oneof=eu|us,defaultvalue=eu,env=TEST_MANIFEST_REGION" help:"target region"`
+		Workers int      `arg:"min=1,max=64,defaultvalue=4"`
+		Token   string   `arg:"secret,defaultvalue=hunter2,config=auth.token"`
+		Text    bool     `arg:"xor=format"`
+		Yaml    bool     `arg:"xor=format"`
+		App     string   `arg:"pos=0,required" help:"app to deploy"`
+		Hosts   []string `arg:"pos=rest"`
+	}
+	rootCmd := &cobra.Command{Use: "app", Short: "manages apps"}
+	deployCmd := &cobra.Command{Use: "deploy", Aliases: []string{"d"}, Run: func(*cobra.Command, []string) {}}
+	rootCmd.AddCommand(deployCmd)
+	if err := AttachStructArgs(rootCmd, &rootArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AttachStructArgs(deployCmd, &deployArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		format string
+		golden string
+	}{
+		{format: "yaml", golden: "manifest.yaml.golden"},
+		{format: "json", golden: "manifest.json.golden"},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WriteManifest(&out, rootCmd, test.format); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.golden, out.Bytes())
+		})
+	}
+	if err := WriteManifest(&bytes.Buffer{}, rootCmd, "xml"); err == nil {
+		t.Error("WriteManifest wrote an unknown format")
+	}
+}
@@ -0,0 +1,102 @@
+package cobraargs
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/spf13/pflag"
+)
+
+// NameStyle is how the default long name of a field is derived from its Go name, MaxRetryCount for example.
+type NameStyle int32
+
+const (
+	// DefaultNameStyle uses the style set by SetNameStyle, LowerCamelCase unless changed.
+	DefaultNameStyle NameStyle = iota
+	// LowerCamelCase derives maxRetryCount.
+	LowerCamelCase
+	// KebabCase derives max-retry-count, the cobra convention.
+	KebabCase
+	// SnakeCase derives max_retry_count.
+	SnakeCase
+)
+
+var nameStyle atomic.Int32
+
+// SetNameStyle sets, for the whole process, the style of the default long names of fields without a 'longname' tag, unless Options.NameStyle sets one for an attachment.
+func SetNameStyle(style NameStyle) {
+	nameStyle.Store(int32(style))
+	clearArgCache()
+}
+
+// CurrentNameStyle reports the style set by SetNameStyle.
+func CurrentNameStyle() NameStyle {
+	if style := NameStyle(nameStyle.Load()); style != DefaultNameStyle {
+		return style
+	}
+	return LowerCamelCase
+}
+
+// NormalizeFunc returns a pflag normalization func rewriting every flag name into style, so --maxRetries, --max-retries and --max_retries all name the flag max-retries under KebabCase. It eases switching naming styles, as the names of the old style keep working. Options.NormalizeNames installs it on the command being attached to.
+func NormalizeFunc(style NameStyle) func(flags *pflag.FlagSet, name string) pflag.NormalizedName {
+	return func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if style == DefaultNameStyle {
+			style = CurrentNameStyle()
+		}
+		if style != LowerCamelCase {
+			return pflag.NormalizedName(longNameOf(name, style))
+		}
+		words := nameWords(name)
+		for index, word := range words {
+			words[index] = strings.ToLower(word)
+			if index > 0 {
+				words[index] = strings.ToUpper(word[0:1]) + words[index][1:]
+			}
+		}
+		return pflag.NormalizedName(strings.Join(words, ""))
+	}
+}
+
+// longNameOf derives the default long name of the Go field name in style.
+func longNameOf(fieldName string, style NameStyle) string {
+	if style == DefaultNameStyle {
+		style = CurrentNameStyle()
+	}
+	switch style {
+	case KebabCase:
+		return strings.ToLower(strings.Join(nameWords(fieldName), "-"))
+	case SnakeCase:
+		return strings.ToLower(strings.Join(nameWords(fieldName), "_"))
+	}
+	return strings.ToLower(fieldName[0:1]) + fieldName[1:]
+}
+
+// nameWords splits a name at '-', '_' and '.' and where its case changes, keeping acronyms together: HTTPServerURL gives HTTP, Server and URL.
+func nameWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for index, r := range runes {
+		if r == '-' || r == '_' || r == '.' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			previous := runes[index-1]
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
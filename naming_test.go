@@ -0,0 +1,51 @@
+package cobraargs
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLongNameOf(t *testing.T) {
+	tests := []struct {
+		field string
+		style NameStyle
+		want  string
+	}{
+		{field: "MaxRetryCount", style: LowerCamelCase, want: "maxRetryCount"},
+		{field: "MaxRetryCount", style: KebabCase, want: "max-retry-count"},
+		{field: "MaxRetryCount", style: SnakeCase, want: "max_retry_count"},
+		{field: "HTTPServerURL", style: KebabCase, want: "http-server-url"},
+		{field: "DBHost", style: SnakeCase, want: "db_host"},
+		{field: "Name", style: KebabCase, want: "name"},
+	}
+	for _, test := range tests {
+		t.Run(test.field+"/"+test.want, func(t *testing.T) {
+			if got := longNameOf(test.field, test.style); got != test.want {
+				t.Errorf("longNameOf(%q, %v) = %q, want %q", test.field, test.style, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeFunc(t *testing.T) {
+	tests := []struct {
+		name  string
+		style NameStyle
+		want  string
+	}{
+		{name: "maxRetries", style: KebabCase, want: "max-retries"},
+		{name: "max_retries", style: KebabCase, want: "max-retries"},
+		{name: "max-retries", style: KebabCase, want: "max-retries"},
+		{name: "max-retries", style: SnakeCase, want: "max_retries"},
+		{name: "max-retries", style: LowerCamelCase, want: "maxRetries"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalize := NormalizeFunc(test.style)
+			if got := normalize(pflag.NewFlagSet("", pflag.ContinueOnError), test.name); string(got) != test.want {
+				t.Errorf("NormalizeFunc(%v)(%q) = %q, want %q", test.style, test.name, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package cobraargs
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// structField is an arg field of a struct or of a struct nested in it, as found by structArgFields.
+type structField struct {
+	reflect.StructField
+	// parent is the struct declaring the field, named in errors
+	parent reflect.Type
+	// prefix is prepended to the long name, from the 'prefix' tags of the enclosing struct fields
+	prefix string
+	// path is the dotted field names from the outer struct, the key of Options.Overrides
+	path string
+}
+
+// structArgFields lists the arg fields of structType with their Index relative to it. It descends into embedded structs without an 'arg' or 'help' tag and into struct fields tagged 'prefix', as in `arg:"prefix=db-"` making the Host field of the nested struct the --db-host flag, so a struct can be reused without its flags colliding. Struct types bound as a single flag, such as time.Time or types implementing encoding.TextUnmarshaler, are never descended into.
+func structArgFields(structType reflect.Type, options Options) ([]structField, error) {
+	fields, err := appendStructArgFields(nil, structType, nil, "", "", options)
+	if err != nil {
+		return nil, err
+	}
+	for path := range options.Overrides {
+		if !hasFieldPath(fields, path) {
+			return nil, fmt.Errorf("struct %v has no arg field %v named by Options.Overrides", structType.Name(), path)
+		}
+	}
+	return fields, nil
+}
+
+func appendStructArgFields(fields []structField, structType reflect.Type, index []int, path, prefix string, options Options) ([]structField, error) {
+	for position := 0; position < structType.NumField(); position++ {
+		field := structType.Field(position)
+		field.Index = append(append([]int{}, index...), position)
+		fieldPath := path + field.Name
+		if nested, nestedPrefix, err := nestedStructPrefix(field, options); err != nil {
+			return nil, inStruct(err, structType)
+		} else if nested {
+			nestedPath := fieldPath + "."
+			if field.Anonymous {
+				nestedPath = path // promoted fields are named as if declared in the outer struct
+			}
+			if fields, err = appendStructArgFields(fields, field.Type, field.Index, nestedPath, prefix+nestedPrefix, options); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !isArgField(field, options) {
+			continue
+		}
+		fields = append(fields, structField{StructField: field, parent: structType, prefix: prefix, path: fieldPath})
+	}
+	return fields, nil
+}
+
+func hasFieldPath(fields []structField, path string) bool {
+	for _, field := range fields {
+		if field.path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedStructPrefix reports whether field is a struct to descend into and the prefix its tag adds.
+func nestedStructPrefix(field reflect.StructField, options Options) (bool, string, error) {
+	if field.Type.Kind() != reflect.Struct || isFlagStructType(field.Type) || field.Type == commandType {
+		return false, "", nil
+	}
+	if _, isSubcommand := field.Tag.Lookup(CommandTagKey); isSubcommand {
+		return false, "", nil
+	}
+	_, hasArg := options.argTag(field)
+	_, hasHelp := field.Tag.Lookup(options.helpTagKey())
+	if !hasArg && !hasHelp {
+		return field.Anonymous, "", nil
+	}
+	if field.PkgPath != "" && !field.Anonymous {
+		return false, "", nil
+	}
+	arg, err := parseArgFromField(field, options)
+	if err != nil {
+		return false, "", err
+	}
+	return arg.HasPrefix, arg.Prefix, nil
+}
+
+// isFlagStructType reports whether values of the struct type are bound as a single flag.
+func isFlagStructType(structType reflect.Type) bool {
+	if structType == reflect.TypeOf(time.Time{}) || structType == reflect.TypeOf(net.IPNet{}) {
+		return true
+	}
+	if _, registered := lookupConverter(structType); registered {
+		return true
+	}
+	pointerType := reflect.PtrTo(structType)
+	return pointerType.Implements(textUnmarshalerType) || pointerType.Implements(pflagValueType)
+}
+
+// parseStructArg parses the tag of field with its Options.Overrides items, prefixing its long name and aliases.
+func parseStructArg(field structField, options Options) (Argument, error) {
+	rawArgStr, _ := options.argTag(field.StructField)
+	if override, has := options.Overrides[field.path]; has {
+		rawArgStr = strings.TrimPrefix(rawArgStr+","+override, ",")
+	}
+	arg, err := parseArgTag(field.StructField, rawArgStr, options.NameStyle)
+	if err != nil || field.prefix == "" {
+		return arg, err
+	}
+	arg.LongName = field.prefix + arg.LongName
+	for index, alias := range arg.Aliases {
+		arg.Aliases[index] = field.prefix + alias
+	}
+	return arg, nil
+}
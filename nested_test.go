@@ -0,0 +1,61 @@
+package cobraargs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type dbConfig struct {
+	Host string `arg:"defaultvalue=localhost"`
+	Port int    `arg:"defaultvalue=5432"`
+}
+
+type CommonArgs struct {
+	Verbose bool `arg:"shortname=v"`
+}
+
+type nestedArgs struct {
+	CommonArgs
+	Primary dbConfig  `arg:"prefix=primary-"`
+	Replica dbConfig  `arg:"prefix=replica-"`
+	Since   time.Time `arg:"layout=2006-01-02"`
+}
+
+func TestNestedStructArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		wantDef string
+	}{
+		{name: "embedded", flag: "verbose", wantDef: "false"},
+		{name: "prefixed", flag: "primary-host", wantDef: "localhost"},
+		{name: "second prefix", flag: "replica-port", wantDef: "5432"},
+		{name: "flag struct type", flag: "since"},
+	}
+	var args nestedArgs
+	cmd := &cobra.Command{Use: "app"}
+	if err := AttachStructArgs(cmd, &args); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flag := cmd.Flags().Lookup(test.flag)
+			if flag == nil {
+				t.Fatalf("no flag --%v", test.flag)
+			}
+			if test.wantDef != "" && flag.DefValue != test.wantDef {
+				t.Errorf("flag --%v default %q, want %q", test.flag, flag.DefValue, test.wantDef)
+			}
+		})
+	}
+	cmd.Run = func(*cobra.Command, []string) {}
+	cmd.SetArgs([]string{"-v", "--primary-host", "a", "--replica-port", "1", "--since", "2024-02-03"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !args.Verbose || args.Primary.Host != "a" || args.Replica.Port != 1 || args.Replica.Host != "localhost" || args.Since.Day() != 3 {
+		t.Errorf("got %+v", args)
+	}
+}
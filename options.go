@@ -0,0 +1,66 @@
+package cobraargs
+
+import (
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+// Options configures which struct tags the struct functions read, so cobraargs can share a struct with other tag based libraries. The zero value reads the 'arg' and 'help' tags.
+type Options struct {
+	// TagKey names the tag holding the key=value settings, 'arg' when empty.
+	TagKey string
+	// HelpTagKey names the tag holding the help text, 'help' when empty.
+	HelpTagKey string
+	// EnvPrefix, when set, binds every field without an 'env' tag to the environment variable <EnvPrefix>_<UPPER_SNAKE_LONGNAME>. A tag of env=- opts a field out.
+	EnvPrefix string
+	// FromDir, when set, is the 'fromdir' directory of every field without one, such as the mount point of a Kubernetes ConfigMap. A tag of fromdir=- opts a field out.
+	FromDir string
+	// Overrides, keyed by field name, adds tag items after those of the field's own tag, so later items win, as in {"Format": "defaultvalue=json,longname=output-format"}. It lets a shared struct such as OutputOptions get a different default or name on each command it is attached to. Fields of nested structs are keyed by their dotted path, such as "Primary.Host".
+	Overrides map[string]string
+	// AutoShortNames gives every visible flag field without a 'shortname' an unused letter: the first letter of its long name if free, then its other letters, then the rest of the alphabet. Letters are handed out in field order after the explicit short names are reserved, so the result only changes when the struct does.
+	AutoShortNames bool
+	// NameStyle is the style of the default long names of fields without a 'longname' tag, the one set by SetNameStyle when zero.
+	NameStyle NameStyle
+	// Dialect is the tag syntax read, the cobraargs one unless set to GoArgDialect or KongDialect for a struct written for those libraries. TagKey does not apply to the other dialects.
+	Dialect TagDialect
+	// NormalizeNames installs NormalizeFunc for NameStyle on the command and its subcommands, so a flag can be given in any naming style.
+	NormalizeNames bool
+
+	// persistent makes every field a persistent flag, as NewCommand does for a struct with subcommand fields
+	persistent bool
+}
+
+func (options Options) tagKey() string {
+	if options.TagKey == "" {
+		return "arg"
+	}
+	return options.TagKey
+}
+
+func (options Options) helpTagKey() string {
+	if options.HelpTagKey == "" {
+		return "help"
+	}
+	return options.HelpTagKey
+}
+
+// AttachStructArgsWithOptions is AttachStructArgs reading the tags named by options.
+func AttachStructArgsWithOptions(cmd *cobra.Command, target interface{}, options Options) error {
+	return attachStructArgs(cmd, target, options)
+}
+
+// UnmarshalWithOptions is Unmarshal reading the tags named by options.
+func UnmarshalWithOptions(cmd *cobra.Command, target interface{}, options Options) error {
+	return unmarshal(cmd, target, options)
+}
+
+// ValidateStructWithOptions is ValidateStruct reading the tags named by options.
+func ValidateStructWithOptions(structType reflect.Type, options Options) error {
+	return validateStruct(structType, options)
+}
+
+// ParseArgFromFieldWithOptions is ParseArgFromField reading the tag named by options.
+func ParseArgFromFieldWithOptions(field reflect.StructField, options Options) (Argument, error) {
+	return parseArgFromField(field, options)
+}
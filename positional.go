@@ -0,0 +1,328 @@
+package cobraargs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// positionalField is a struct field tagged 'pos' or 'positional', waiting for its position to be settled.
+type positionalField struct {
+	name    string
+	arg     Argument
+	rawHelp string
+	value   interface{}
+}
+
+// positionalArgs holds the positional fields attached to a command, in position order. Their values are bound as flags of a holder command that never runs, so every field type, default value and tag parses as it does for a flag.
+type positionalArgs struct {
+	holder *cobra.Command
+	args   []Argument
+	rest   *Argument
+	// passthrough takes the arguments after the "--" terminator, which then count for none of the other fields
+	passthrough *Argument
+	// required is how many arguments cmd.Args requires, counting one for a required rest field
+	required int
+}
+
+var (
+	positionalArgsLock sync.Mutex
+	positionalArgsOf   = map[*cobra.Command]*positionalArgs{}
+)
+
+func lookupPositionalArgs(cmd *cobra.Command) (*positionalArgs, bool) {
+	positionalArgsLock.Lock()
+	defer positionalArgsLock.Unlock()
+	positional, has := positionalArgsOf[cmd]
+	return positional, has
+}
+
+// positionalFlags returns the flag set holding the positional field values of cmd, if any.
+func positionalFlags(cmd *cobra.Command) *pflag.FlagSet {
+	positional, has := lookupPositionalArgs(cmd)
+	if !has {
+		return nil
+	}
+	return positional.holder.Flags()
+}
+
+// attachPositionalArgs binds the positional fields of a struct to cmd. Fields tagged 'pos=N' take argument N and those tagged 'positional' take the free positions in field order. Positions start at 0 and leave no gaps, and optional fields, those not tagged 'required', come after the required ones. A single []string field tagged 'pos=rest' takes every argument after them, and a single []string field tagged 'passthrough' every argument after a "--" terminator, as a wrapper forwarding them to a child process needs. cmd gets matching cobra.Args validation, a ValidArgsFunction completing each position from its 'complete' or 'oneof' tag and, when its Use is a bare name, the argument names appended as <NAME>, [NAME] or NAME....
+func attachPositionalArgs(cmd *cobra.Command, parmType reflect.Type, fields []positionalField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if _, has := lookupPositionalArgs(cmd); has {
+		return fmt.Errorf("struct %v has positional fields but command %v already has positional fields attached", parmType.Name(), cmd.Name())
+	}
+	fields, rest, err := splitSliceField(parmType, fields, "pos=rest", func(arg Argument) bool { return arg.Rest })
+	if err != nil {
+		return err
+	}
+	fields, passthrough, err := splitSliceField(parmType, fields, "passthrough", func(arg Argument) bool { return arg.Passthrough })
+	if err != nil {
+		return err
+	}
+	ordered, err := orderPositionalFields(parmType, fields)
+	if err != nil {
+		return err
+	}
+	holder := &cobra.Command{Use: cmd.Name()}
+	positional := &positionalArgs{holder: holder}
+	required := 0
+	for index, field := range ordered {
+		if field.arg.Required {
+			if index > required {
+				return newArgError(ErrInvalidTagValue, parmType, field.name, "pos", "", "field %v.%v is a required positional argument after the optional positional field %v", parmType.Name(), field.name, ordered[required].name)
+			}
+			required++
+		}
+		field.arg.Required = false // checked by cmd.Args rather than as a flag
+		field.arg.Persistent = false
+		if err := attachFieldArg(holder, parmType, field.name, field.arg, field.rawHelp, field.value); err != nil {
+			return err
+		}
+		positional.args = append(positional.args, field.arg)
+	}
+	var generated cobra.PositionalArgs
+	if rest != nil {
+		if rest.arg.Required {
+			if required < len(ordered) {
+				return newArgError(ErrInvalidTagValue, parmType, rest.name, "pos", "rest", "field %v.%v is a required positional argument after the optional positional field %v", parmType.Name(), rest.name, ordered[required].name)
+			}
+			required++
+		}
+		rest.arg.Required = false
+		rest.arg.Persistent = false
+		if err := attachFieldArg(holder, parmType, rest.name, rest.arg, rest.rawHelp, rest.value); err != nil {
+			return err
+		}
+		positional.rest = &rest.arg
+		generated = cobra.MinimumNArgs(required)
+	} else if required == len(ordered) {
+		generated = cobra.ExactArgs(required)
+	} else {
+		generated = cobra.RangeArgs(required, len(ordered))
+	}
+	if passthrough != nil {
+		passthrough.arg.Required = false
+		passthrough.arg.Persistent = false
+		if err := attachFieldArg(holder, parmType, passthrough.name, passthrough.arg, passthrough.rawHelp, passthrough.value); err != nil {
+			return err
+		}
+		positional.passthrough = &passthrough.arg
+		generated = beforeDash(generated)
+	}
+	positional.required = required
+	positionalArgsLock.Lock()
+	positionalArgsOf[cmd] = positional
+	positionalArgsLock.Unlock()
+
+	if cmd.Args != nil {
+		generated = cobra.MatchAll(cmd.Args, generated)
+	}
+	cmd.Args = generated
+	if cmd.ValidArgsFunction == nil && len(cmd.ValidArgs) == 0 && positional.completes() {
+		cmd.ValidArgsFunction = positional.complete
+	}
+	if cmd.Use != "" && !strings.Contains(cmd.Use, " ") {
+		cmd.Use += " " + positional.usage(required)
+	}
+	next, nextRun := cmd.PreRunE, cmd.PreRun
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := positional.set(cmd, args); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(cmd, args)
+		}
+		if nextRun != nil {
+			nextRun(cmd, args)
+		}
+		return nil
+	}
+	cmd.PreRun = nil
+	return nil
+}
+
+// splitSliceField takes the field selected by the tag, such as 'pos=rest', out of fields. At most one field may have it and it must be a []string.
+func splitSliceField(parmType reflect.Type, fields []positionalField, tag string, selected func(Argument) bool) ([]positionalField, *positionalField, error) {
+	var found *positionalField
+	others := make([]positionalField, 0, len(fields))
+	for index, field := range fields {
+		if !selected(field.arg) {
+			others = append(others, field)
+			continue
+		}
+		if found != nil {
+			return nil, nil, newArgError(ErrInvalidTagValue, parmType, field.name, tag, "", "field %v.%v has '%v' which field %v already has", parmType.Name(), field.name, tag, found.name)
+		}
+		if _, ok := field.value.(*[]string); !ok {
+			return nil, nil, newArgError(ErrInvalidTagValue, parmType, field.name, tag, "", "field %v.%v has '%v' but is of type %v rather than []string", parmType.Name(), field.name, tag, reflect.TypeOf(field.value).Elem())
+		}
+		found = &fields[index]
+	}
+	return others, found, nil
+}
+
+// beforeDash applies validate to the arguments before the "--" terminator only.
+func beforeDash(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args = args[:dash]
+		}
+		return validate(cmd, args)
+	}
+}
+
+// orderPositionalFields places the fields with an explicit 'pos' first, then fills the remaining positions with the others in field order.
+func orderPositionalFields(parmType reflect.Type, fields []positionalField) ([]positionalField, error) {
+	ordered := make([]positionalField, len(fields))
+	taken := make([]bool, len(fields))
+	var unplaced []positionalField
+	for _, field := range fields {
+		if !field.arg.HasPosition {
+			unplaced = append(unplaced, field)
+			continue
+		}
+		position := field.arg.Position
+		if position >= len(fields) {
+			return nil, newArgError(ErrInvalidTagValue, parmType, field.name, "pos", fmt.Sprint(position), "field %v.%v has 'pos' %v but the struct has only %v positional fields", parmType.Name(), field.name, position, len(fields))
+		}
+		if taken[position] {
+			return nil, newArgError(ErrInvalidTagValue, parmType, field.name, "pos", fmt.Sprint(position), "field %v.%v has 'pos' %v which field %v already has", parmType.Name(), field.name, position, ordered[position].name)
+		}
+		ordered[position] = field
+		taken[position] = true
+	}
+	position := 0
+	for _, field := range unplaced {
+		for taken[position] {
+			position++
+		}
+		ordered[position] = field
+		taken[position] = true
+	}
+	return ordered, nil
+}
+
+// completes reports whether any positional field has a 'complete' or 'oneof' tag.
+func (positional *positionalArgs) completes() bool {
+	for _, arg := range positional.all() {
+		if argCompletion(arg) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (positional *positionalArgs) all() []Argument {
+	args := append([]Argument{}, positional.args...)
+	if positional.rest != nil {
+		args = append(args, *positional.rest)
+	}
+	if positional.passthrough != nil {
+		args = append(args, *positional.passthrough)
+	}
+	return args
+}
+
+// complete completes the argument being typed from the field at its position, offering files when that field has no completion and nothing once every position is taken. Arguments after a "--" terminator complete from the 'passthrough' field.
+func (positional *positionalArgs) complete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var arg *Argument
+	switch {
+	// cobra parses the words being completed with a "--" appended, so the word right after a typed "--" cannot be told apart from one without it
+	case positional.passthrough != nil && cmd.ArgsLenAtDash() >= 0 && cmd.ArgsLenAtDash() < len(args):
+		arg = positional.passthrough
+	case len(args) < len(positional.args):
+		arg = &positional.args[len(args)]
+	case positional.rest != nil:
+		arg = positional.rest
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	complete := argCompletion(*arg)
+	if complete == nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return complete(cmd, args, toComplete)
+}
+
+// set parses the command line arguments into the positional fields, then, as the hooks of a command do for its flags, fills the fields not given from their 'env' and 'fromdir' tags and runs their validation tags.
+func (positional *positionalArgs) set(cmd *cobra.Command, args []string) error {
+	if err := positional.parse(cmd, args); err != nil {
+		return err
+	}
+	if err := ApplyFlagSetEnvArgs(positional.holder.Flags()); err != nil {
+		return err
+	}
+	return ValidateFlags(positional.holder)
+}
+
+// parse sets the positional fields from the command line arguments.
+func (positional *positionalArgs) parse(cmd *cobra.Command, args []string) error {
+	flags := positional.holder.Flags()
+	if dash := cmd.ArgsLenAtDash(); positional.passthrough != nil && dash >= 0 {
+		if err := setSliceArgs(flags, *positional.passthrough, args[dash:]); err != nil {
+			return err
+		}
+		args = args[:dash]
+	}
+	for index, arg := range positional.args {
+		if index >= len(args) {
+			break
+		}
+		flag := flags.Lookup(arg.LongName)
+		if err := flag.Value.Set(args[index]); err != nil {
+			return fmt.Errorf("invalid argument %q for %v: %v", args[index], positionalName(arg), err)
+		}
+		flag.Changed = true
+	}
+	if positional.rest == nil || len(args) <= len(positional.args) {
+		return nil
+	}
+	return setSliceArgs(flags, *positional.rest, args[len(positional.args):])
+}
+
+// setSliceArgs replaces the value of a []string positional field, rather than appending as setting a slice flag again does, so a command executed twice does not keep the arguments of the first run.
+func setSliceArgs(flags *pflag.FlagSet, arg Argument, values []string) error {
+	flag := flags.Lookup(arg.LongName)
+	if err := unwrapValue(flag.Value).(pflag.SliceValue).Replace(values); err != nil {
+		return fmt.Errorf("invalid arguments %q for %v: %v", values, positionalName(arg), err)
+	}
+	flag.Changed = true
+	return nil
+}
+
+func (positional *positionalArgs) usage(required int) string {
+	names := make([]string, len(positional.args))
+	for index, arg := range positional.args {
+		if index < required {
+			names[index] = "<" + positionalName(arg) + ">"
+		} else {
+			names[index] = "[" + positionalName(arg) + "]"
+		}
+	}
+	if positional.rest != nil {
+		if required > len(positional.args) {
+			names = append(names, "<"+positionalName(*positional.rest)+">...")
+		} else {
+			names = append(names, "["+positionalName(*positional.rest)+"]...")
+		}
+	}
+	if positional.passthrough != nil {
+		names = append(names, "[-- "+positionalName(*positional.passthrough)+"...]")
+	}
+	return strings.Join(names, " ")
+}
+
+// positionalName is the 'placeholder' of a positional field, or its long name in upper case.
+func positionalName(arg Argument) string {
+	if arg.Placeholder != "" {
+		return arg.Placeholder
+	}
+	return strings.ToUpper(arg.LongName)
+}
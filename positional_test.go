@@ -0,0 +1,60 @@
+package cobraargs
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPositionalArgs(t *testing.T) {
+	type copyArgs struct {
+		Source string   `arg:"pos=0,required,pattern=^[a-z]+$"`
+		Count  int      `arg:"pos=1,min=1,max=5,defaultvalue=1"`
+		Target string   `arg:"pos=2,env=TEST_POSITIONAL_TARGET"`
+		Rest   []string `arg:"pos=rest"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		want    copyArgs
+		wantErr bool
+	}{
+		{name: "required only", args: []string{"src"}, want: copyArgs{Source: "src", Count: 1}},
+		{name: "all", args: []string{"src", "3", "dst", "a", "b"}, want: copyArgs{Source: "src", Count: 3, Target: "dst", Rest: []string{"a", "b"}}},
+		{name: "env", args: []string{"src", "2"}, env: "fromenv", want: copyArgs{Source: "src", Count: 2, Target: "fromenv"}},
+		{name: "argument over env", args: []string{"src", "2", "dst"}, env: "fromenv", want: copyArgs{Source: "src", Count: 2, Target: "dst"}},
+		{name: "missing required", args: []string{}, wantErr: true},
+		{name: "pattern", args: []string{"SRC"}, wantErr: true},
+		{name: "max", args: []string{"src", "6"}, wantErr: true},
+		{name: "min", args: []string{"src", "0"}, wantErr: true},
+		{name: "not a number", args: []string{"src", "x"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.env != "" {
+				t.Setenv("TEST_POSITIONAL_TARGET", test.env)
+			}
+			var args copyArgs
+			cmd := &cobra.Command{Use: "copy", Run: func(*cobra.Command, []string) {}}
+			cmd.SilenceErrors, cmd.SilenceUsage = true, true
+			if err := AttachStructArgs(cmd, &args); err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Execute succeeded with %+v, want an error", args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if args.Source != test.want.Source || args.Count != test.want.Count || args.Target != test.want.Target || len(args.Rest) != len(test.want.Rest) {
+				t.Errorf("got %+v, want %+v", args, test.want)
+			}
+		})
+	}
+}
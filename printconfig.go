@@ -0,0 +1,81 @@
+package cobraargs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// PrintConfigFlagName is the long name of the flag declared by AttachPrintConfigFlag.
+const PrintConfigFlagName = "print-config"
+
+// printedValue is one flag in the --print-config output.
+type printedValue struct {
+	Value  interface{} `json:"value" yaml:"value"`
+	Source Source      `json:"source" yaml:"source"`
+}
+
+// ErrConfigPrinted is returned by Execute when --print-config printed the config instead of running the command. It is not a failure: test for it with errors.Is and exit with status 0.
+var ErrConfigPrinted = errors.New("config printed")
+
+// AttachPrintConfigFlag declares a persistent --print-config flag on cmd. Given as --print-config or --print-config=yaml, or as --print-config=json, it makes the command print the resolved value and Source of every attached flag, after the env and config file steps, and return ErrConfigPrinted instead of running. Cobra is told not to print that error or the usage for it.
+func AttachPrintConfigFlag(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup(PrintConfigFlagName) != nil || cmd.PersistentFlags().Lookup(PrintConfigFlagName) != nil {
+		return fmt.Errorf("command %v already has a --%v flag", cmd.Name(), PrintConfigFlagName)
+	}
+	format := ""
+	cmd.PersistentFlags().StringVar(&format, PrintConfigFlagName, "", "optional: print the resolved flag values as `yaml` or json and exit")
+	cmd.PersistentFlags().Lookup(PrintConfigFlagName).NoOptDefVal = "yaml"
+	installPostPreRunHook(cmd, stagePrintConfig, func(cmd *cobra.Command) error {
+		if format == "" {
+			return nil
+		}
+		if err := ApplyEnvArgs(cmd); err != nil {
+			return err
+		}
+		if err := PrintConfig(cmd.OutOrStdout(), cmd, format); err != nil {
+			return err
+		}
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return ErrConfigPrinted
+	})
+	return nil
+}
+
+// PrintConfig writes the current value and Source of every attached flag of cmd to w as 'yaml' or 'json'. The value of a 'secret' flag is written as RedactedValue.
+func PrintConfig(w io.Writer, cmd *cobra.Command, format string) error {
+	values := map[string]printedValue{}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Annotations[attachedAnnotation] == nil {
+			return
+		}
+		var value interface{} = flag.Value.String()
+		if sliceValue, ok := unwrapValue(flag.Value).(pflag.SliceValue); ok {
+			value = sliceValue.GetSlice()
+		}
+		if isSecretFlag(flag) {
+			value = RedactedValue
+		}
+		values[flag.Name] = printedValue{Value: value, Source: FlagSource(cmd, flag.Name)}
+	})
+	switch format {
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(values); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(values)
+	}
+	return fmt.Errorf("print config format [%v] is not yaml or json", format)
+}
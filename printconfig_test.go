@@ -0,0 +1,69 @@
+package cobraargs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAttachPrintConfigFlag(t *testing.T) {
+	type serverArgs struct {
+		Port     int    `arg:"defaultvalue=8080"`
+		Password string `arg:"secret,defaultvalue=hunter2"`
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  error
+		wantRun  bool
+		contains []string
+		excludes []string
+	}{
+		{name: "runs without the flag", args: []string{"--port", "9090"}, wantRun: true},
+		{name: "yaml", args: []string{"--print-config", "--port", "9090"}, wantErr: ErrConfigPrinted, contains: []string{"port:", "9090", "source: flag", RedactedValue}, excludes: []string{"hunter2"}},
+		{name: "json", args: []string{"--print-config=json"}, wantErr: ErrConfigPrinted, contains: []string{`"port"`, `"8080"`, `"source": "default"`}},
+		{name: "bad format", args: []string{"--print-config=xml"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args serverArgs
+			var ran bool
+			var out bytes.Buffer
+			cmd := &cobra.Command{Use: "server", Run: func(*cobra.Command, []string) { ran = true }}
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			if err := AttachStructArgs(cmd, &args); err != nil {
+				t.Fatal(err)
+			}
+			if err := AttachPrintConfigFlag(cmd); err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			err := cmd.Execute()
+			switch {
+			case test.wantErr != nil && !errors.Is(err, test.wantErr):
+				t.Fatalf("Execute error %v, want %v", err, test.wantErr)
+			case test.wantErr == nil && test.wantRun && err != nil:
+				t.Fatal(err)
+			case test.wantErr == nil && !test.wantRun && err == nil:
+				t.Fatal("Execute succeeded, want an error")
+			}
+			if ran != test.wantRun {
+				t.Errorf("command ran %v, want %v", ran, test.wantRun)
+			}
+			for _, want := range test.contains {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output %q does not contain %q", out.String(), want)
+				}
+			}
+			for _, unwanted := range test.excludes {
+				if strings.Contains(out.String(), unwanted) {
+					t.Errorf("output %q contains %q", out.String(), unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ProfileFlagName is the long name of the flag declared by AttachProfileFlag.
+const ProfileFlagName = "profile"
+
+// AttachProfileFlag declares a persistent --profile flag on cmd, defaulting to defaultProfile, that picks a named profile from the YAML, JSON or TOML file at path, e.g. ~/.myapp/profiles.yaml, like the AWS CLI profiles. Each top level key of the file is a profile holding values keyed as in a config file, which become defaults for every attached flag still unset after the command line, env and config file steps. A leading ~/ in path is the user's home directory. A missing file is ignored unless --profile was given, and an unknown profile name is an error.
+func AttachProfileFlag(cmd *cobra.Command, path, defaultProfile string) error {
+	if cmd.Flags().Lookup(ProfileFlagName) != nil || cmd.PersistentFlags().Lookup(ProfileFlagName) != nil {
+		return fmt.Errorf("command %v already has a --%v flag", cmd.Name(), ProfileFlagName)
+	}
+	profile := ""
+	cmd.PersistentFlags().StringVar(&profile, ProfileFlagName, defaultProfile, fmt.Sprintf("optional: named profile (`NAME`) from %v", path))
+	installPostPreRunHook(cmd, stageProfile, func(cmd *cobra.Command) error {
+		if profile == "" {
+			return nil
+		}
+		if err := ApplyEnvArgs(cmd); err != nil {
+			return err
+		}
+		profiles, err := readConfigFile(expandHome(path))
+		if errors.Is(err, fs.ErrNotExist) && !cmd.Flag(ProfileFlagName).Changed {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values, ok := profiles[profile].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("profile file %v has no profile [%v]", path, profile)
+		}
+		return applyConfigValues(cmd, values, SourceProfile, fmt.Sprintf("profile %v in %v", profile, path))
+	})
+	return nil
+}
+
+// expandHome replaces a leading ~/ in path with the user's home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,13 @@
+module github.com/doug4j/cobraargs/protoflags
+
+go 1.20
+
+require (
+	github.com/spf13/cobra v1.8.1
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+)
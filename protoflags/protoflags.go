@@ -0,0 +1,186 @@
+// Package protoflags exposes the fields of a protobuf request message as cobra flags, so a gRPC client CLI needs no hand written arg struct:
+//
+//	request := &pb.ListBooksRequest{}
+//	if err := protoflags.Attach(cmd, request); err != nil {
+//		return err
+//	}
+//
+// after which --page_size 10 --filter 'author=x' set request.PageSize and request.Filter before the command runs.
+package protoflags
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Attach attaches a flag to cmd for each scalar, enum and repeated scalar field of msg, named by the proto field name, such as page_size. Setting a flag sets the field of msg, and a repeated field's flag appends each comma separated item. The default of a flag is the value msg already holds, else the field's declared default, enum flags accept the value names or numbers and complete the names, and proto2 required fields are required flags. Message, group and map fields are left out.
+func Attach(cmd *cobra.Command, msg proto.Message) error {
+	message := msg.ProtoReflect()
+	fields := message.Descriptor().Fields()
+	for index := 0; index < fields.Len(); index++ {
+		field := fields.Get(index)
+		if field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			continue
+		}
+		name := string(field.Name())
+		if cmd.Flags().Lookup(name) != nil {
+			return fmt.Errorf("field %v of %v is the flag --%v which command %v already has", field.Name(), message.Descriptor().FullName(), name, cmd.Name())
+		}
+		value := &fieldValue{message: message, field: field}
+		flag := cmd.Flags().VarPF(value, name, "", usage(field))
+		flag.DefValue = value.String()
+		if field.Kind() == protoreflect.BoolKind && !field.IsList() {
+			flag.NoOptDefVal = "true"
+		}
+		if field.Cardinality() == protoreflect.Required {
+			if err := cmd.MarkFlagRequired(name); err != nil {
+				return err
+			}
+		}
+		if field.Kind() == protoreflect.EnumKind {
+			names := enumNames(field.Enum())
+			if err := cmd.RegisterFlagCompletionFunc(name, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+				return names, cobra.ShellCompDirectiveNoFileComp
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// usage is the help of the flag of field: the leading comment of the field when the descriptor carries source info, prefixed as cobraargs prefixes help, with the enum value names.
+func usage(field protoreflect.FieldDescriptor) string {
+	help := "optional: "
+	if field.Cardinality() == protoreflect.Required {
+		help = "MANDATORY: "
+	}
+	if file := field.ParentFile(); file != nil {
+		help += strings.TrimSpace(file.SourceLocations().ByDescriptor(field).LeadingComments)
+	}
+	if field.Kind() == protoreflect.EnumKind {
+		help += fmt.Sprintf(" (one of %v)", strings.Join(enumNames(field.Enum()), "|"))
+	}
+	return help
+}
+
+func enumNames(enum protoreflect.EnumDescriptor) []string {
+	values := enum.Values()
+	names := make([]string, values.Len())
+	for index := range names {
+		names[index] = string(values.Get(index).Name())
+	}
+	return names
+}
+
+// fieldValue is a pflag.Value setting one field of a message.
+type fieldValue struct {
+	message protoreflect.Message
+	field   protoreflect.FieldDescriptor
+}
+
+func (value *fieldValue) Set(text string) error {
+	if !value.field.IsList() {
+		parsed, err := parseScalar(value.field, text)
+		if err != nil {
+			return err
+		}
+		value.message.Set(value.field, parsed)
+		return nil
+	}
+	list := value.message.Mutable(value.field).List()
+	for _, item := range strings.Split(text, ",") {
+		parsed, err := parseScalar(value.field, item)
+		if err != nil {
+			return err
+		}
+		list.Append(parsed)
+	}
+	return nil
+}
+
+func (value *fieldValue) String() string {
+	if !value.field.IsList() {
+		return formatScalar(value.field, value.message.Get(value.field))
+	}
+	list := value.message.Get(value.field).List()
+	items := make([]string, list.Len())
+	for index := range items {
+		items[index] = formatScalar(value.field, list.Get(index))
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func (value *fieldValue) Type() string {
+	name := value.field.Kind().String()
+	if value.field.Kind() == protoreflect.EnumKind {
+		name = string(value.field.Enum().Name())
+	}
+	if value.field.IsList() {
+		return name + "Slice"
+	}
+	return name
+}
+
+// parseScalar parses text as a value of the kind of field.
+func parseScalar(field protoreflect.FieldDescriptor, text string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		parsed, err := strconv.ParseBool(text)
+		return protoreflect.ValueOfBool(parsed), err
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		if enumValue := values.ByName(protoreflect.Name(text)); enumValue != nil {
+			return protoreflect.ValueOfEnum(enumValue.Number()), nil
+		}
+		number, err := strconv.ParseInt(text, 10, 32)
+		if err != nil || values.ByNumber(protoreflect.EnumNumber(number)) == nil {
+			return protoreflect.Value{}, fmt.Errorf("value [%v] is not one of %v", text, strings.Join(enumNames(field.Enum()), "|"))
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(number)), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		parsed, err := strconv.ParseInt(text, 0, 32)
+		return protoreflect.ValueOfInt32(int32(parsed)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		parsed, err := strconv.ParseInt(text, 0, 64)
+		return protoreflect.ValueOfInt64(parsed), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		parsed, err := strconv.ParseUint(text, 0, 32)
+		return protoreflect.ValueOfUint32(uint32(parsed)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		parsed, err := strconv.ParseUint(text, 0, 64)
+		return protoreflect.ValueOfUint64(parsed), err
+	case protoreflect.FloatKind:
+		parsed, err := strconv.ParseFloat(text, 32)
+		return protoreflect.ValueOfFloat32(float32(parsed)), err
+	case protoreflect.DoubleKind:
+		parsed, err := strconv.ParseFloat(text, 64)
+		return protoreflect.ValueOfFloat64(parsed), err
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(text), nil
+	case protoreflect.BytesKind:
+		parsed, err := base64.StdEncoding.DecodeString(text)
+		return protoreflect.ValueOfBytes(parsed), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("field %v has unsupported kind %v", field.Name(), field.Kind())
+}
+
+// formatScalar writes a value of the kind of field as parseScalar reads it.
+func formatScalar(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.Bytes())
+	}
+	return value.String()
+}
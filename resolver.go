@@ -0,0 +1,39 @@
+package cobraargs
+
+import (
+	"context"
+	"sync"
+)
+
+// Resolver fetches the value a remote reference points to, such as the Vault reference secret/data/app#api_key, so secrets stay out of flags, env and config files.
+type Resolver interface {
+	Resolve(ctx context.Context, reference string) (string, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(ctx context.Context, reference string) (string, error)
+
+// Resolve calls f.
+func (f ResolverFunc) Resolve(ctx context.Context, reference string) (string, error) {
+	return f(ctx, reference)
+}
+
+var (
+	resolversLock sync.RWMutex
+	resolvers     = map[string]Resolver{}
+)
+
+// RegisterResolver makes fields tagged 'indirect=<scheme>' resolve values of the form <scheme>:<reference> with resolver when the value is set, whether from the command line, the environment or a config file, so resolution happens before the command runs. Register before attaching, as the 'indirect' tag only accepts known schemes. The file, env and exec schemes are built in and cannot be replaced.
+func RegisterResolver(scheme string, resolver Resolver) {
+	resolversLock.Lock()
+	defer resolversLock.Unlock()
+	resolvers[scheme] = resolver
+	clearArgCache()
+}
+
+func lookupResolver(scheme string) (Resolver, bool) {
+	resolversLock.RLock()
+	defer resolversLock.RUnlock()
+	resolver, has := resolvers[scheme]
+	return resolver, has
+}
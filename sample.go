@@ -0,0 +1,170 @@
+package cobraargs
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// sampleNode is a key of a sample config file, either a value with its help or a table of nested keys.
+type sampleNode struct {
+	name     string
+	comment  string
+	value    string
+	children []*sampleNode
+}
+
+func (node *sampleNode) child(name string) *sampleNode {
+	for _, child := range node.children {
+		if child.name == name {
+			return child
+		}
+	}
+	child := &sampleNode{name: name}
+	node.children = append(node.children, child)
+	return child
+}
+
+// WriteSampleConfig writes a commented config file skeleton for structType (a struct or pointer to struct) in format 'yaml' or 'toml'. Every tagged field appears under its 'config' key path, or its long name, with its help text as a comment and its default value, ready to ship as an example next to the CLI.
+func WriteSampleConfig(w io.Writer, structType reflect.Type, format string) error {
+	format = strings.ToLower(format)
+	if format != "yaml" && format != "toml" {
+		return fmt.Errorf("sample config format [%v] is not yaml or toml", format)
+	}
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("type [%v] is not a struct", structType)
+	}
+	cmd := &cobra.Command{Use: structType.Name()}
+	if err := AttachStructArgs(cmd, reflect.New(structType).Interface()); err != nil {
+		return err
+	}
+	root := &sampleNode{}
+	fields, err := structArgs(structType, Options{})
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			return inStruct(err, field.parent)
+		}
+		if arg.Positional {
+			continue
+		}
+		path := arg.LongName
+		if arg.Config != "" {
+			path = arg.Config
+		}
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			node = node.child(name)
+		}
+		node.comment = rationalizeHelp(arg, field.Tag.Get("help"))
+		node.value = sampleValue(argFlags(cmd, arg).Lookup(arg.LongName), format)
+	}
+	if format == "yaml" {
+		return writeSampleYAML(w, root, "")
+	}
+	return writeSampleTOML(w, root, "")
+}
+
+// sampleValue renders the default value of flag as a YAML or TOML literal.
+func sampleValue(flag *pflag.Flag, format string) string {
+	valueType := flag.Value.Type()
+	if sliceValue, ok := unwrapValue(flag.Value).(pflag.SliceValue); ok {
+		items := sliceValue.GetSlice()
+		for index, item := range items {
+			items[index] = sampleScalar(strings.TrimSuffix(strings.TrimSuffix(valueType, "Slice"), "Array"), item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	if strings.HasPrefix(valueType, "stringTo") {
+		pairs := strings.Split(strings.Trim(flag.DefValue, "[]"), ",")
+		sort.Strings(pairs)
+		assign := ": "
+		if format == "toml" {
+			assign = " = "
+		}
+		var items []string
+		for _, pair := range pairs {
+			if key, value, ok := strings.Cut(pair, "="); ok {
+				items = append(items, strconv.Quote(key)+assign+sampleScalar(strings.ToLower(strings.TrimPrefix(valueType, "stringTo")), value))
+			}
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return sampleScalar(valueType, flag.DefValue)
+}
+
+func sampleScalar(valueType, value string) string {
+	switch {
+	case valueType == "bool", valueType == "count", strings.HasPrefix(valueType, "int"), strings.HasPrefix(valueType, "uint"), strings.HasPrefix(valueType, "float"):
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func writeSampleComment(w io.Writer, indent, comment string) error {
+	_, err := fmt.Fprintf(w, "%v# %v\n", indent, strings.TrimSpace(comment))
+	return err
+}
+
+func writeSampleYAML(w io.Writer, node *sampleNode, indent string) error {
+	for _, child := range node.children {
+		if len(child.children) > 0 {
+			if _, err := fmt.Fprintf(w, "%v%v:\n", indent, child.name); err != nil {
+				return err
+			}
+			if err := writeSampleYAML(w, child, indent+"  "); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeSampleComment(w, indent, child.comment); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%v%v: %v\n", indent, child.name, child.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSampleTOML(w io.Writer, node *sampleNode, table string) error {
+	for _, child := range node.children {
+		if len(child.children) > 0 {
+			continue
+		}
+		if err := writeSampleComment(w, "", child.comment); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%v = %v\n", child.name, child.value); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.children {
+		if len(child.children) == 0 {
+			continue
+		}
+		name := child.name
+		if table != "" {
+			name = table + "." + child.name
+		}
+		if _, err := fmt.Fprintf(w, "\n[%v]\n", name); err != nil {
+			return err
+		}
+		if err := writeSampleTOML(w, child, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
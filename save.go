@@ -0,0 +1,133 @@
+package cobraargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// SaveConfig writes the current value of every attached flag of cmd to the config file at path, under its 'config' key path or long name, so a later run with --config reads them back. The format is 'yaml', 'json' or 'toml', taken from the extension of path when empty. 'secret' flags are left out. A leading ~/ in path is the user's home directory and missing directories are created.
+func SaveConfig(cmd *cobra.Command, path, format string) error {
+	path = expandHome(path)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	values := map[string]interface{}{}
+	visited := map[*pflag.Flag]bool{}
+	var err error
+	visit := func(flag *pflag.Flag) {
+		if err != nil || visited[flag] || flag.Annotations[attachedAnnotation] == nil || isSecretFlag(flag) {
+			return
+		}
+		visited[flag] = true
+		key := flag.Name
+		if paths := flag.Annotations[configAnnotation]; len(paths) > 0 {
+			key = paths[0]
+		}
+		err = storeConfigValue(values, key, flagConfigValue(flag))
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.PersistentFlags().VisitAll(visit)
+	if err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	switch format {
+	case "yaml", "yml":
+		encoder := yaml.NewEncoder(&content)
+		encoder.SetIndent(2)
+		err = encoder.Encode(values)
+	case "json":
+		encoder := json.NewEncoder(&content)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(values)
+	case "toml":
+		err = toml.NewEncoder(&content).Encode(values)
+	default:
+		return fmt.Errorf("config format [%v] is not yaml, json or toml", format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content.Bytes(), 0o644)
+}
+
+// storeConfigValue puts value at the dotted key path in values, creating nested maps as needed.
+func storeConfigValue(values map[string]interface{}, key string, value interface{}) error {
+	names := strings.Split(key, ".")
+	for _, name := range names[:len(names)-1] {
+		child, has := values[name]
+		if !has {
+			child = map[string]interface{}{}
+			values[name] = child
+		}
+		nested, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("config key %v is both a value and a table", key)
+		}
+		values = nested
+	}
+	if _, has := values[names[len(names)-1]]; has {
+		return fmt.Errorf("config key %v is used by more than one flag", key)
+	}
+	values[names[len(names)-1]] = value
+	return nil
+}
+
+// flagConfigValue returns the current value of flag typed for a config file: numbers and bools as such, slices as lists and stringTo maps as maps.
+func flagConfigValue(flag *pflag.Flag) interface{} {
+	valueType := flag.Value.Type()
+	if sliceValue, ok := unwrapValue(flag.Value).(pflag.SliceValue); ok {
+		items := sliceValue.GetSlice()
+		elementType := strings.TrimSuffix(strings.TrimSuffix(valueType, "Slice"), "Array")
+		values := make([]interface{}, len(items))
+		for index, item := range items {
+			values[index] = typedConfigValue(elementType, item)
+		}
+		return values
+	}
+	if strings.HasPrefix(valueType, "stringTo") {
+		elementType := strings.ToLower(strings.TrimPrefix(valueType, "stringTo"))
+		values := map[string]interface{}{}
+		for _, pair := range strings.Split(strings.Trim(flag.Value.String(), "[]"), ",") {
+			if key, value, ok := strings.Cut(pair, "="); ok {
+				values[key] = typedConfigValue(elementType, value)
+			}
+		}
+		return values
+	}
+	return typedConfigValue(valueType, flag.Value.String())
+}
+
+func typedConfigValue(valueType, value string) interface{} {
+	var typed interface{}
+	var err error
+	switch {
+	case valueType == "bool":
+		typed, err = strconv.ParseBool(value)
+	case valueType == "count", strings.HasPrefix(valueType, "int"):
+		typed, err = strconv.ParseInt(value, 10, 64)
+	case strings.HasPrefix(valueType, "uint"):
+		typed, err = strconv.ParseUint(value, 10, 64)
+	case strings.HasPrefix(valueType, "float"):
+		typed, err = strconv.ParseFloat(value, 64)
+	default:
+		return value
+	}
+	if err != nil {
+		return value
+	}
+	return typed
+}
@@ -0,0 +1,94 @@
+package cobraargs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSaveConfig(t *testing.T) {
+	type savedArgs struct {
+		Name    string `arg:"defaultvalue=bob"`
+		Port    int    `arg:"defaultvalue=80"`
+		Ratio   float64
+		Debug   bool
+		Tags    []string
+		Labels  map[string]string
+		Host    string `arg:"config=server.host"`
+		Token   string `arg:"secret"`
+		Retries int    `arg:"defaultvalue=3"`
+	}
+	args := []string{
+		"--name", "alice", "--port", "8080", "--ratio", "0.5", "--debug", "--tags", "a", "--tags", "b",
+		"--labels", "env=prod", "--host", "example.com", "--token", "hunter2",
+	}
+	want := savedArgs{
+		Name: "alice", Port: 8080, Ratio: 0.5, Debug: true, Tags: []string{"a", "b"},
+		Labels: map[string]string{"env": "prod"}, Host: "example.com", Retries: 3,
+	}
+	tests := []struct {
+		name      string
+		file      string
+		format    string
+		wantSaved []string
+	}{
+		{name: "yaml", file: "config.yaml", wantSaved: []string{"name: alice", "retries: 3", "server:\n  host: example.com"}},
+		{name: "json", file: "config.json", wantSaved: []string{`"name": "alice"`, `"retries": 3`, `"host": "example.com"`}},
+		{name: "toml", file: "config.toml", wantSaved: []string{`name = "alice"`, "retries = 3", "[server]"}},
+		{name: "format over extension", file: "config.conf", format: "yaml", wantSaved: []string{"name: alice"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "nested", test.file)
+			var saved savedArgs
+			saveCmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+			if err := AttachStructArgs(saveCmd, &saved); err != nil {
+				t.Fatal(err)
+			}
+			saveCmd.SetArgs(args)
+			if err := saveCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if err := SaveConfig(saveCmd, path, test.format); err != nil {
+				t.Fatal(err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(content), "hunter2") || strings.Contains(string(content), "token") {
+				t.Errorf("saved config has the secret flag:\n%s", content)
+			}
+			for _, wantSaved := range test.wantSaved {
+				if !strings.Contains(string(content), wantSaved) {
+					t.Errorf("saved config does not contain %q:\n%s", wantSaved, content)
+				}
+			}
+			if test.format != "" {
+				return
+			}
+			var loaded savedArgs
+			loadCmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+			if err := AttachStructArgs(loadCmd, &loaded); err != nil {
+				t.Fatal(err)
+			}
+			if err := AttachConfigFlag(loadCmd, path); err != nil {
+				t.Fatal(err)
+			}
+			loadCmd.SetArgs(nil)
+			if err := loadCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded, want) {
+				t.Errorf("loaded %+v, want %+v", loaded, want)
+			}
+		})
+	}
+	if err := SaveConfig(&cobra.Command{Use: "app"}, filepath.Join(t.TempDir(), "config.ini"), ""); err == nil {
+		t.Error("SaveConfig wrote an unknown format")
+	}
+}
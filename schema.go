@@ -0,0 +1,169 @@
+package cobraargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect GenJSONSchema declares.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaProperty is a property of the generated JSON Schema, kept in field order.
+type schemaProperty struct {
+	name   string
+	schema map[string]interface{}
+}
+
+// schemaProperties marshals as a JSON object keeping the field order, so forms rendered from the schema list the flags as the struct does.
+type schemaProperties []schemaProperty
+
+func (properties schemaProperties) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for index, property := range properties {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(property.name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(property.schema)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(schema)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// GenJSONSchema returns a JSON Schema of the flags of structType (a struct or pointer to struct) for web frontends rendering forms that match the CLI. Each flag is a property named by its long name with its type, default, help text as description, 'oneof' choices as enum, 'min' and 'max' as minimum and maximum, and 'minlen', 'maxlen' and 'pattern' constraints, and 'required' flags are listed as required. 'secret' flags are writeOnly and have no default.
+func GenJSONSchema(structType reflect.Type) ([]byte, error) {
+	docs, err := structFlagDocs(structType)
+	if err != nil {
+		return nil, err
+	}
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	properties := schemaProperties{}
+	required := []string{}
+	for _, doc := range docs {
+		properties = append(properties, schemaProperty{name: doc.longName, schema: flagSchema(doc)})
+		if doc.required {
+			required = append(required, doc.longName)
+		}
+	}
+	schema := struct {
+		Schema     string           `json:"$schema"`
+		Title      string           `json:"title"`
+		Type       string           `json:"type"`
+		Properties schemaProperties `json:"properties"`
+		Required   []string         `json:"required,omitempty"`
+	}{Schema: jsonSchemaDraft, Title: structType.Name(), Type: "object", Properties: properties, Required: required}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// flagSchema is the JSON Schema of the property of one flag.
+func flagSchema(doc flagDoc) map[string]interface{} {
+	flagType := doc.flag.Value.Type()
+	schema := scalarSchema(flagType)
+	sliceValue, isSlice := unwrapValue(doc.flag.Value).(pflag.SliceValue)
+	switch {
+	case isSlice:
+		itemType := strings.TrimSuffix(strings.TrimSuffix(flagType, "Slice"), "Array")
+		schema = map[string]interface{}{"type": "array", "items": scalarSchema(itemType)}
+	case strings.HasPrefix(flagType, "stringTo"):
+		schema = map[string]interface{}{"type": "object", "additionalProperties": scalarSchema(strings.ToLower(strings.TrimPrefix(flagType, "stringTo")))}
+	}
+	if doc.help != "" {
+		schema["description"] = doc.help
+	}
+	if isSecretFlag(doc.flag) {
+		schema["writeOnly"] = true
+	} else if doc.arg.HasDefaultValue {
+		switch {
+		case isSlice:
+			items := []interface{}{}
+			for _, item := range sliceValue.GetSlice() {
+				items = append(items, schemaValue(schema["items"].(map[string]interface{}), item))
+			}
+			schema["default"] = items
+		case schema["type"] != "object":
+			schema["default"] = schemaValue(schema, doc.flag.DefValue)
+		}
+	}
+	target := schema
+	if isSlice {
+		target = schema["items"].(map[string]interface{})
+	}
+	if len(doc.arg.OneOf) > 0 {
+		choices := make([]interface{}, len(doc.arg.OneOf))
+		for index, choice := range doc.arg.OneOf {
+			choices[index] = schemaValue(target, choice)
+		}
+		target["enum"] = choices
+	}
+	if target["type"] == "integer" || target["type"] == "number" {
+		if minimum, err := strconv.ParseFloat(doc.arg.Min, 64); err == nil {
+			target["minimum"] = minimum
+		}
+		if maximum, err := strconv.ParseFloat(doc.arg.Max, 64); err == nil {
+			target["maximum"] = maximum
+		}
+	}
+	if target["type"] == "string" {
+		if doc.arg.MinLen > 0 {
+			target["minLength"] = doc.arg.MinLen
+		}
+		if doc.arg.MaxLen > 0 {
+			target["maxLength"] = doc.arg.MaxLen
+		}
+		if doc.arg.Pattern != "" {
+			target["pattern"] = doc.arg.Pattern
+		}
+	}
+	return schema
+}
+
+// scalarSchema maps a pflag value type to a JSON Schema type, a string unless it is a boolean or a number.
+func scalarSchema(valueType string) map[string]interface{} {
+	switch {
+	case valueType == "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case valueType == "count", strings.HasPrefix(valueType, "int"):
+		return map[string]interface{}{"type": "integer"}
+	case strings.HasPrefix(valueType, "uint"):
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case strings.HasPrefix(valueType, "float"):
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{"type": "string"}
+}
+
+// schemaValue converts the flag text value to the JSON type of schema, leaving it a string when it does not parse.
+func schemaValue(schema map[string]interface{}, value string) interface{} {
+	switch schema["type"] {
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
@@ -0,0 +1,33 @@
+package cobraargs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenJSONSchema(t *testing.T) {
+	type schemaArgs struct {
+		Name    string         `arg:"required,minlen=2,maxlen=8,pattern=^[a-z]+$" help:"the name"`
+		Level   string         `arg:"oneof=debug|info,defaultvalue=info"`
+		Workers int            `arg:"min=1,max=64,defaultvalue=4"`
+		Ratio   float64        `arg:"max=0.5"`
+		Size    uint16         `arg:"defaultvalue=512"`
+		Token   string         `arg:"secret,defaultvalue=hunter2"`
+		Ports   []int          `arg:"min=1,max=65535,defaultvalue=80|443,sep=|"`
+		Zones   []string       `arg:"oneof=a|b"`
+		Labels  map[string]int `help:"label weights"`
+		Debug   bool
+	}
+	schema, err := GenJSONSchema(reflect.TypeOf(&schemaArgs{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(schema) {
+		t.Fatalf("GenJSONSchema returned invalid JSON:\n%s", schema)
+	}
+	checkGolden(t, "schema.json.golden", append(schema, '\n'))
+	if _, err := GenJSONSchema(reflect.TypeOf("")); err == nil {
+		t.Error("GenJSONSchema accepted a type that is not a struct")
+	}
+}
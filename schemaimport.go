@@ -0,0 +1,273 @@
+package cobraargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// SchemaArgs holds the flags attached from a JSON Schema by AttachJSONSchemaArgs, bound to the fields of a struct built from the schema at runtime.
+type SchemaArgs struct {
+	target     reflect.Value
+	properties []string
+}
+
+// AttachJSONSchemaArgs attaches a flag to cmd for each property of schema, for plugin style CLIs whose options are defined server side. schema is either a JSON Schema object, whose string, integer, number and boolean properties, arrays of those and objects of string or integer values become flags, or an OpenAPI parameter list, whose parameters become flags the same way from their schema. A property's type, default, description, enum, minimum, maximum, minLength, maxLength and pattern become the flag type and its 'defaultvalue', help, 'oneof', 'min', 'max', 'minlen', 'maxlen' and 'pattern' tags, required properties are 'required', and writeOnly or password properties are 'secret'. The flags are named after the properties.
+func AttachJSONSchemaArgs(cmd *cobra.Command, schema []byte) (*SchemaArgs, error) {
+	structType, properties, err := schemaStructType(schema)
+	if err != nil {
+		return nil, err
+	}
+	target := reflect.New(structType)
+	if err := AttachStructArgs(cmd, target.Interface()); err != nil {
+		return nil, err
+	}
+	return &SchemaArgs{target: target.Elem(), properties: properties}, nil
+}
+
+// Arguments returns the Argument of each flag, in property name order.
+func (schemaArgs *SchemaArgs) Arguments() []Argument {
+	structType := schemaArgs.target.Type()
+	args := make([]Argument, 0, structType.NumField())
+	for index := 0; index < structType.NumField(); index++ {
+		arg, err := ParseArgFromField(structType.Field(index))
+		if err != nil {
+			continue // parsed without error when attached
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// Values returns the value of each flag keyed by property name, as a string, int, float64, bool, slice or map according to the property type.
+func (schemaArgs *SchemaArgs) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(schemaArgs.properties))
+	for index, property := range schemaArgs.properties {
+		values[property] = schemaArgs.target.Field(index).Interface()
+	}
+	return values
+}
+
+// schemaStructType builds a struct type with an arg tagged field for each property of schema, returning the property names in field order.
+func schemaStructType(schema []byte) (reflect.Type, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schema))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, nil, fmt.Errorf("json schema: %v", err)
+	}
+	properties, required, err := schemaPropertiesOf(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]reflect.StructField, 0, len(names))
+	taken := map[string]bool{}
+	for _, name := range names {
+		field, err := schemaField(name, properties[name], required[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		for taken[field.Name] {
+			field.Name += "_"
+		}
+		taken[field.Name] = true
+		fields = append(fields, field)
+	}
+	return reflect.StructOf(fields), names, nil
+}
+
+// schemaPropertiesOf reads the properties and required property names of a JSON Schema object or an OpenAPI parameter list.
+func schemaPropertiesOf(document interface{}) (map[string]map[string]interface{}, map[string]bool, error) {
+	properties := map[string]map[string]interface{}{}
+	required := map[string]bool{}
+	switch document := document.(type) {
+	case map[string]interface{}:
+		declared, _ := document["properties"].(map[string]interface{})
+		for name, property := range declared {
+			schema, ok := property.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("json schema property %v is not an object", name)
+			}
+			properties[name] = schema
+		}
+		names, _ := document["required"].([]interface{})
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	case []interface{}:
+		for index, item := range document {
+			parameter, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("openapi parameter %v is not an object", index)
+			}
+			name, _ := parameter["name"].(string)
+			if name == "" {
+				return nil, nil, fmt.Errorf("openapi parameter %v has no name", index)
+			}
+			schema := map[string]interface{}{}
+			if declared, ok := parameter["schema"].(map[string]interface{}); ok {
+				for key, value := range declared {
+					schema[key] = value
+				}
+			}
+			if description, ok := parameter["description"]; ok {
+				schema["description"] = description
+			}
+			properties[name] = schema
+			required[name] = parameter["required"] == true
+		}
+	default:
+		return nil, nil, fmt.Errorf("json schema is neither an object nor an openapi parameter list")
+	}
+	return properties, required, nil
+}
+
+// schemaField builds the struct field of one property, its tags written from the schema keywords.
+func schemaField(name string, schema map[string]interface{}, required bool) (reflect.StructField, error) {
+	fieldType, err := schemaFieldType(schema)
+	if err != nil {
+		return reflect.StructField{}, fmt.Errorf("json schema property %v %v", name, err)
+	}
+	items := []string{"longname=" + escapeTagValue(name)}
+	if required {
+		items = append(items, "required")
+	}
+	if value, has := schema["default"]; has {
+		items = append(items, "sep=\\,", "defaultvalue="+escapeTagValue(schemaText(value)))
+	}
+	if choices, ok := schema["enum"].([]interface{}); ok && len(choices) > 0 {
+		texts := make([]string, len(choices))
+		for index, choice := range choices {
+			texts[index] = schemaText(choice)
+		}
+		items = append(items, "oneof="+escapeTagValue(strings.Join(texts, "|")))
+	}
+	for keyword, key := range map[string]string{"minimum": "min", "maximum": "max", "minLength": "minlen", "maxLength": "maxlen", "pattern": "pattern"} {
+		if value, has := schema[keyword]; has {
+			items = append(items, key+"="+escapeTagValue(schemaText(value)))
+		}
+	}
+	if schema["writeOnly"] == true || schema["format"] == "password" {
+		items = append(items, "secret")
+	}
+	sort.Strings(items[1:])
+	help, _ := schema["description"].(string)
+	if help == "" {
+		help, _ = schema["title"].(string)
+	}
+	tag := fmt.Sprintf(`arg:%v help:%v`, strconv.Quote(strings.Join(items, ",")), strconv.Quote(help))
+	return reflect.StructField{Name: schemaFieldName(name), Type: fieldType, Tag: reflect.StructTag(tag)}, nil
+}
+
+// schemaFieldType maps the type of a property to the field type binding it.
+func schemaFieldType(schema map[string]interface{}) (reflect.Type, error) {
+	switch schemaType(schema) {
+	case "string":
+		return reflect.TypeOf(""), nil
+	case "integer":
+		return reflect.TypeOf(0), nil
+	case "number":
+		return reflect.TypeOf(0.0), nil
+	case "boolean":
+		return reflect.TypeOf(false), nil
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		switch schemaType(items) {
+		case "string":
+			return reflect.TypeOf([]string{}), nil
+		case "integer":
+			return reflect.TypeOf([]int{}), nil
+		case "number":
+			return reflect.TypeOf([]float64{}), nil
+		}
+		return nil, fmt.Errorf("is an array of unsupported items")
+	case "object":
+		values, _ := schema["additionalProperties"].(map[string]interface{})
+		switch schemaType(values) {
+		case "string":
+			return reflect.TypeOf(map[string]string{}), nil
+		case "integer":
+			return reflect.TypeOf(map[string]int{}), nil
+		}
+		return nil, fmt.Errorf("is an object of unsupported values")
+	}
+	return nil, fmt.Errorf("has unsupported type %v", schema["type"])
+}
+
+// schemaType is the type of schema, the first one other than null when it lists several, and string for an untyped enum.
+func schemaType(schema map[string]interface{}) string {
+	switch declared := schema["type"].(type) {
+	case string:
+		return declared
+	case []interface{}:
+		for _, item := range declared {
+			if item, ok := item.(string); ok && item != "null" {
+				return item
+			}
+		}
+	case nil:
+		if _, isEnum := schema["enum"]; isEnum {
+			return "string"
+		}
+	}
+	return ""
+}
+
+// schemaText writes a JSON value as tag text, joining array items with commas and object members as key=value pairs.
+func schemaText(value interface{}) string {
+	switch value := value.(type) {
+	case []interface{}:
+		texts := make([]string, len(value))
+		for index, item := range value {
+			texts[index] = schemaText(item)
+		}
+		return strings.Join(texts, ",")
+	case map[string]interface{}:
+		pairs := make([]string, 0, len(value))
+		for key, item := range value {
+			pairs = append(pairs, key+"="+schemaText(item))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// escapeTagValue escapes the backslashes, quotes and commas of value for splitArgTag.
+func escapeTagValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, ",", `\,`).Replace(value)
+}
+
+// schemaFieldName derives an exported Go field name from a property name, as MaxRetries from max-retries.
+func schemaFieldName(property string) string {
+	var name strings.Builder
+	for _, word := range strings.FieldsFunc(property, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		name.WriteRune(unicode.ToUpper(runes[0]))
+		name.WriteString(string(runes[1:]))
+	}
+	fieldName := name.String()
+	if fieldName == "" || !unicode.IsUpper([]rune(fieldName)[0]) {
+		fieldName = "Field" + fieldName
+	}
+	if len(fieldName) < 2 {
+		fieldName += "Arg"
+	}
+	return fieldName
+}
@@ -0,0 +1,138 @@
+package cobraargs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAttachJSONSchemaArgs(t *testing.T) {
+	const objectSchema = `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$", "description": "the name"},
+			"level": {"enum": ["debug", "info"], "default": "info"},
+			"max-retries": {"type": ["integer", "null"], "minimum": 0, "maximum": 5, "default": 3},
+			"ratio": {"type": "number"},
+			"tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]},
+			"weights": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"debug": {"type": "boolean", "title": "debug output"},
+			"token": {"type": "string", "writeOnly": true}
+		}
+	}`
+	const parameterList = `[
+		{"name": "region", "in": "query", "required": true, "description": "target region", "schema": {"type": "string", "enum": ["eu", "us"]}},
+		{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 10}}
+	]`
+	tests := []struct {
+		name       string
+		schema     string
+		args       []string
+		want       map[string]interface{}
+		wantErr    string
+		wantAttach string
+	}{
+		{
+			name:   "object defaults",
+			schema: objectSchema,
+			args:   []string{"--name", "bob"},
+			want: map[string]interface{}{
+				"name": "bob", "level": "info", "max-retries": 3, "ratio": 0.0, "tags": []string{"a", "b"},
+				"weights": map[string]int{}, "debug": false, "token": "",
+			},
+		},
+		{
+			name:   "object flags",
+			schema: objectSchema,
+			args:   []string{"--name", "bob", "--level", "debug", "--max-retries", "5", "--ratio", "0.5", "--tags", "c", "--weights", "x=1", "--debug", "--token", "t"},
+			want: map[string]interface{}{
+				"name": "bob", "level": "debug", "max-retries": 5, "ratio": 0.5, "tags": []string{"c"},
+				"weights": map[string]int{"x": 1}, "debug": true, "token": "t",
+			},
+		},
+		{name: "required", schema: objectSchema, wantErr: "name"},
+		{name: "enum", schema: objectSchema, args: []string{"--name", "bob", "--level", "trace"}, wantErr: "trace"},
+		{name: "maximum", schema: objectSchema, args: []string{"--name", "bob", "--max-retries", "6"}, wantErr: "between 0 and 5"},
+		{name: "pattern", schema: objectSchema, args: []string{"--name", "Bob"}, wantErr: "name"},
+		{name: "openapi parameters", schema: parameterList, args: []string{"--region", "eu"}, want: map[string]interface{}{"region": "eu", "limit": 10}},
+		{name: "invalid JSON", schema: `{`, wantAttach: "json schema"},
+		{name: "unsupported type", schema: `{"properties": {"when": {"type": "object"}}}`, wantAttach: "unsupported values"},
+		{name: "property not an object", schema: `{"properties": {"when": true}}`, wantAttach: "is not an object"},
+		{name: "parameter without name", schema: `[{"in": "query"}]`, wantAttach: "has no name"},
+		{name: "neither", schema: `"string"`, wantAttach: "neither"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "plugin", Run: func(*cobra.Command, []string) {}}
+			cmd.SilenceErrors, cmd.SilenceUsage = true, true
+			schemaArgs, err := AttachJSONSchemaArgs(cmd, []byte(test.schema))
+			if test.wantAttach != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantAttach) {
+					t.Fatalf("AttachJSONSchemaArgs error %v, want one containing %q", err, test.wantAttach)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			err = cmd.Execute()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Execute error %v, want one containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := schemaArgs.Values(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Values() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSchemaArgsArguments(t *testing.T) {
+	cmd := &cobra.Command{Use: "plugin"}
+	schemaArgs, err := AttachJSONSchemaArgs(cmd, []byte(`{"required": ["name"], "properties": {"name": {"type": "string", "description": "the name"}, "token": {"type": "string", "format": "password"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := schemaArgs.Arguments()
+	if len(args) != 2 {
+		t.Fatalf("Arguments() has %v arguments, want 2", len(args))
+	}
+	if args[0].LongName != "name" || !args[0].Required || args[0].Secret {
+		t.Errorf("Arguments()[0] = %+v, want the required name", args[0])
+	}
+	if args[1].LongName != "token" || args[1].Required || !args[1].Secret {
+		t.Errorf("Arguments()[1] = %+v, want the secret token", args[1])
+	}
+	if usage := cmd.Flags().Lookup("name").Usage; !strings.Contains(usage, "the name") {
+		t.Errorf("flag --name usage %q, want the description", usage)
+	}
+}
+
+func TestSchemaFieldName(t *testing.T) {
+	tests := []struct {
+		property string
+		want     string
+	}{
+		{property: "max-retries", want: "MaxRetries"},
+		{property: "db_host", want: "DbHost"},
+		{property: "x", want: "XArg"},
+		{property: "2fa", want: "Field2fa"},
+		{property: "-", want: "Field"},
+	}
+	for _, test := range tests {
+		t.Run(test.property, func(t *testing.T) {
+			if got := schemaFieldName(test.property); got != test.want {
+				t.Errorf("schemaFieldName(%q) = %q, want %q", test.property, got, test.want)
+			}
+		})
+	}
+}
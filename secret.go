@@ -0,0 +1,74 @@
+package cobraargs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// RedactedValue replaces the value of a 'secret' flag wherever this package would otherwise show it.
+const RedactedValue = "*****"
+
+// secretAnnotation marks a flag whose value must never be shown.
+const secretAnnotation = "cobraargs_secret"
+
+// secretHookAnnotation marks a command whose flag error func already redacts secret values.
+const secretHookAnnotation = "cobraargs_secret_hook"
+
+func isSecretFlag(flag *pflag.Flag) bool {
+	return flag.Annotations[secretAnnotation] != nil
+}
+
+// markSecretArg hides the default value of a 'secret' flag from help.
+func markSecretArg(flags *pflag.FlagSet, arg Argument) error {
+	if !arg.Secret {
+		return nil
+	}
+	if err := flags.SetAnnotation(arg.LongName, secretAnnotation, []string{"true"}); err != nil {
+		return err
+	}
+	flag := flags.Lookup(arg.LongName)
+	switch flag.DefValue {
+	case "", "0", "false", "[]":
+	default:
+		flag.DefValue = RedactedValue
+	}
+	return nil
+}
+
+// installSecretHook makes cmd redact the value of a 'secret' flag from flag parse errors.
+func installSecretHook(cmd *cobra.Command, arg Argument) {
+	if !arg.Secret || cmd.Annotations[secretHookAnnotation] != "" {
+		return
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[secretHookAnnotation] = "true"
+	next := cmd.FlagErrorFunc()
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return next(cmd, redactFlagError(cmd, err))
+	})
+}
+
+// redactFlagError replaces a pflag parse error about a secret flag, which quotes the bad value, with one that does not.
+func redactFlagError(cmd *cobra.Command, err error) error {
+	message := err.Error()
+	var redacted error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if redacted == nil && isSecretFlag(flag) && strings.Contains(message, fmt.Sprintf("--%v\" flag", flag.Name)) {
+			redacted = fmt.Errorf("invalid argument %v for \"--%v\" flag", RedactedValue, flag.Name)
+		}
+	})
+	if redacted != nil {
+		return redacted
+	}
+	return err
+}
+
+// secretSetError is the error for a secret flag that rejected an env or config file value, without the value or the parse error quoting it.
+func secretSetError(flag *pflag.Flag) error {
+	return fmt.Errorf("invalid argument %v for \"--%v\" flag", RedactedValue, flag.Name)
+}
@@ -0,0 +1,69 @@
+package cobraargs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// assignShortNames picks the short names of Options.AutoShortNames, keyed by field path. Short names taken, as reported by taken, by explicit 'shortname' tags or by cobra's -h help flag are never picked. owner names where the flags go in errors, such as "command serve".
+func assignShortNames(taken func(shortName string) bool, owner string, structType reflect.Type, fields []structArg, options Options) (map[string]string, error) {
+	used := map[string]bool{"h": true}
+	var wanting []structArg
+	var longNames []string
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			continue // reported when the field is attached
+		}
+		switch {
+		case arg.ShortName != "":
+			used[arg.ShortName] = true
+		case !arg.Positional && !arg.Hidden && arg.Deprecated == "":
+			wanting = append(wanting, field)
+			longNames = append(longNames, arg.LongName)
+		}
+	}
+	shortNames := map[string]string{}
+	for index, field := range wanting {
+		shortName := ""
+		for _, candidate := range shortNameCandidates(longNames[index]) {
+			if !used[candidate] && !taken(candidate) {
+				shortName = candidate
+				break
+			}
+		}
+		if shortName == "" {
+			return nil, fmt.Errorf("field %v.%v has no unused short name left to be given, as every letter is taken on %v", structType.Name(), field.Name, owner)
+		}
+		used[shortName] = true
+		shortNames[field.path] = shortName
+	}
+	return shortNames, nil
+}
+
+// shortNameCandidates lists the letters of longName in order, then the rest of the alphabet.
+func shortNameCandidates(longName string) []string {
+	var candidates []string
+	for _, letter := range strings.ToLower(longName) + "abcdefghijklmnopqrstuvwxyz" {
+		if letter >= 'a' && letter <= 'z' && !containsString(candidates, string(letter)) {
+			candidates = append(candidates, string(letter))
+		}
+	}
+	return candidates
+}
+
+// shortNameTaken reports whether shortName is taken on cmd or by the persistent flags of its ancestors.
+func shortNameTaken(cmd *cobra.Command, shortName string) bool {
+	if lookupCommandShorthand(cmd, shortName) != nil {
+		return true
+	}
+	for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.PersistentFlags().ShorthandLookup(shortName) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,855 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// AttachStructArgs uses reflection to attach a flag for every exported field of the struct pointed to by target: those with an 'arg' or 'help' tag, and untagged fields of a type it binds, so untagged func or channel fields are left alone and arg:"-" skips a field. The flag type is inferred from the field type and each flag is bound directly to its field. Every bad tag, duplicate name and unparsable default value is reported together in the returned error. It is safe to call from several goroutines, as are the Register functions, with attachments to one command, or to commands sharing ancestors, taking turns; only adding commands to a tree while attaching to it is not.
+func AttachStructArgs(cmd *cobra.Command, target interface{}) error {
+	return attachStructArgs(cmd, target, Options{})
+}
+
+func attachStructArgs(cmd *cobra.Command, target interface{}, options Options) error {
+	defer lockCommandTree(cmd)()
+	return attachLockedStructArgs(cmd, target, options)
+}
+
+// attachLockedStructArgs is attachStructArgs for a caller holding the lockCommandTree of cmd.
+func attachLockedStructArgs(cmd *cobra.Command, target interface{}, options Options) error {
+	structValue, err := structPointerValue(target)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	longNames := map[string]string{}
+	shortNames := map[string]string{}
+	xor, together, oneRequired := flagGroups{}, flagGroups{}, flagGroups{}
+	persistent := false
+	var positional []positionalField
+	structType := structValue.Type()
+	if options.NormalizeNames {
+		cmd.SetGlobalNormalizationFunc(NormalizeFunc(options.NameStyle))
+	}
+	fields, err := structArgs(structType, options)
+	if err != nil {
+		return err
+	}
+	var autoShortNames map[string]string
+	if options.AutoShortNames {
+		if autoShortNames, err = assignShortNames(func(shortName string) bool { return shortNameTaken(cmd, shortName) }, commandOwner(cmd), structType, fields, options); err != nil {
+			return err
+		}
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			errs = append(errs, inStruct(err, field.parent))
+			continue
+		}
+		if arg.ShortName == "" {
+			arg.ShortName = autoShortNames[field.path]
+		}
+		variableValue := structValue.FieldByIndex(field.Index).Addr().Interface()
+		if arg.Positional {
+			positional = append(positional, positionalField{name: field.Name, arg: arg, rawHelp: field.Tag.Get(options.helpTagKey()), value: variableValue})
+			continue
+		}
+		if err = checkDuplicateArg(cmd, field.parent, field.Name, arg, longNames, shortNames); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(arg.Env) == 0 && options.EnvPrefix != "" {
+			arg.Env = []string{envName(options.EnvPrefix, arg.LongName)}
+		}
+		if arg.FromDir == "" {
+			arg.FromDir = options.FromDir
+		}
+		arg.Persistent = arg.Persistent || (options.persistent && !arg.Positional)
+		if err = attachCheckedFieldArg(cmd, field.parent, field.Name, arg, field.Tag.Get(options.helpTagKey()), variableValue); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		persistent = persistent || arg.Persistent
+		xor.add(arg.Xor, arg.LongName)
+		together.add(arg.Together, arg.LongName)
+		oneRequired.add(arg.OneRequired, arg.LongName)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := markStructGroups(cmd, structType.Name(), xor, together, oneRequired); err != nil {
+		return err
+	}
+	if validator, ok := target.(StructValidator); ok {
+		installStructValidator(cmd, validator, persistent)
+	}
+	// attached after the struct validator so the positional fields are set before Validate runs
+	return attachPositionalArgs(cmd, structType, positional)
+}
+
+// checkDuplicateArg reports a long or short name already used by another field of the struct or by a flag on cmd, which pflag would otherwise panic on.
+func checkDuplicateArg(cmd *cobra.Command, parmType reflect.Type, variableName string, arg Argument, longNames, shortNames map[string]string) error {
+	if err := checkDuplicateFlag([]*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()}, commandOwner(cmd), parmType, variableName, arg, longNames, shortNames); err != nil || arg.ShortName == "" {
+		return err
+	}
+	// pflag panics when merging an inherited persistent flag whose short name a local flag of another name has
+	for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+		if flag := parent.PersistentFlags().ShorthandLookup(arg.ShortName); flag != nil && flag.Name != arg.LongName {
+			return newArgError(ErrDuplicateName, parmType, variableName, "shortname", arg.ShortName, "field %v.%v has short name [%v] which is already the short name of the persistent flag --%v, %v, on parent command %v", parmType.Name(), variableName, arg.ShortName, flag.Name, flagOwner(flag), parent.Name())
+		}
+	}
+	return nil
+}
+
+// checkDuplicateFlag reports a long or short name already used by another field of the struct or by a flag in one of flagSets, which belong to owner, such as "command serve".
+func checkDuplicateFlag(flagSets []*pflag.FlagSet, owner string, parmType reflect.Type, variableName string, arg Argument, longNames, shortNames map[string]string) error {
+	for index, longName := range argLongNames(arg) {
+		tagKey := "longname"
+		if index > len(arg.Aliases) {
+			tagKey = "negatable"
+		} else if index > 0 {
+			tagKey = "aliases"
+		}
+		if other, has := longNames[longName]; has {
+			return newArgError(ErrDuplicateName, parmType, variableName, tagKey, longName, "field %v.%v has long name [%v] which is already used by field %v", parmType.Name(), variableName, longName, other)
+		}
+		if flag := lookupFlag(flagSets, longName); flag != nil {
+			return newArgError(ErrDuplicateName, parmType, variableName, tagKey, longName, "field %v.%v has long name [%v] which is already %v on %v", parmType.Name(), variableName, longName, flagOwner(flag), owner)
+		}
+		longNames[longName] = parmType.Name() + "." + variableName
+	}
+	if arg.ShortName == "" {
+		return nil
+	}
+	if other, has := shortNames[arg.ShortName]; has {
+		return newArgError(ErrDuplicateName, parmType, variableName, "shortname", arg.ShortName, "field %v.%v has short name [%v] which is already used by field %v", parmType.Name(), variableName, arg.ShortName, other)
+	}
+	if flag := lookupShorthand(flagSets, arg.ShortName); flag != nil {
+		return newArgError(ErrDuplicateName, parmType, variableName, "shortname", arg.ShortName, "field %v.%v has short name [%v] which is already the short name of --%v, %v, on %v", parmType.Name(), variableName, arg.ShortName, flag.Name, flagOwner(flag), owner)
+	}
+	shortNames[arg.ShortName] = parmType.Name() + "." + variableName
+	return nil
+}
+
+// attachLock is the lock of a command being attached to, counting the lockCommandTree calls holding or waiting for it.
+type attachLock struct {
+	sync.RWMutex
+	users int
+}
+
+var (
+	attachLocksLock sync.Mutex
+	// attachLocks holds the lock of each command with an attachment in progress in its tree, dropped once the last one ends so the commands can be collected.
+	attachLocks = map[*cobra.Command]*attachLock{}
+)
+
+// lockCommandTree serializes the attachments to cmd, write locking it and read locking its ancestors, whose persistent flags attaching to cmd reads. The locks are taken root first, so attachments anywhere in one tree cannot deadlock. It returns the func releasing them.
+func lockCommandTree(cmd *cobra.Command) func() {
+	attachLocksLock.Lock()
+	var commands []*cobra.Command
+	var chain []*attachLock
+	for ancestor := cmd; ancestor != nil; ancestor = ancestor.Parent() {
+		lock, has := attachLocks[ancestor]
+		if !has {
+			lock = &attachLock{}
+			attachLocks[ancestor] = lock
+		}
+		lock.users++
+		commands = append(commands, ancestor)
+		chain = append(chain, lock)
+	}
+	attachLocksLock.Unlock()
+	for index := len(chain) - 1; index > 0; index-- {
+		chain[index].RLock()
+	}
+	chain[0].Lock()
+	return func() {
+		chain[0].Unlock()
+		for _, lock := range chain[1:] {
+			lock.RUnlock()
+		}
+		attachLocksLock.Lock()
+		defer attachLocksLock.Unlock()
+		for index, lock := range chain {
+			if lock.users--; lock.users == 0 {
+				delete(attachLocks, commands[index])
+			}
+		}
+	}
+}
+
+// commandOwner names cmd as the owner of its flags in errors.
+func commandOwner(cmd *cobra.Command) string {
+	return "command " + cmd.Name()
+}
+
+// fieldAnnotation records on a flag the Struct.Field it was attached from, named in collision errors.
+const fieldAnnotation = "cobraargs_field"
+
+func lookupCommandFlag(cmd *cobra.Command, longName string) *pflag.Flag {
+	return lookupFlag([]*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()}, longName)
+}
+
+func lookupCommandShorthand(cmd *cobra.Command, shortName string) *pflag.Flag {
+	return lookupShorthand([]*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()}, shortName)
+}
+
+// lookupFlag returns the flag named longName in the first of flagSets that has one.
+func lookupFlag(flagSets []*pflag.FlagSet, longName string) *pflag.Flag {
+	for _, flags := range flagSets {
+		if flag := flags.Lookup(longName); flag != nil {
+			return flag
+		}
+	}
+	return nil
+}
+
+// lookupShorthand returns the flag with the short name shortName in the first of flagSets that has one.
+func lookupShorthand(flagSets []*pflag.FlagSet, shortName string) *pflag.Flag {
+	for _, flags := range flagSets {
+		if flag := flags.ShorthandLookup(shortName); flag != nil {
+			return flag
+		}
+	}
+	return nil
+}
+
+// flagOwner describes who defined flag, for collision errors.
+func flagOwner(flag *pflag.Flag) string {
+	if owner := flag.Annotations[fieldAnnotation]; len(owner) > 0 {
+		return "used by field " + owner[0]
+	}
+	return "a flag"
+}
+
+// AttachAllStructArgs attaches several independent option structs, such as LogOptions and ServerOptions, to cmd. The long and short names of every struct are checked against each other and the flags of cmd before any flag is added, so collisions are all reported, naming both fields, rather than leaving cmd half configured or pflag panicking.
+func AttachAllStructArgs(cmd *cobra.Command, targets ...interface{}) error {
+	defer lockCommandTree(cmd)()
+	var errs []error
+	longNames := map[string]string{}
+	shortNames := map[string]string{}
+	for _, target := range targets {
+		structValue, err := structPointerValue(target)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fields, err := structArgs(structValue.Type(), Options{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, field := range fields {
+			arg, err := field.arg, field.err
+			if err != nil || arg.Positional {
+				continue // tag errors are reported by AttachStructArgs
+			}
+			if err = checkDuplicateArg(cmd, field.parent, field.Name, arg, longNames, shortNames); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, target := range targets {
+		if err := attachLockedStructArgs(cmd, target, Options{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func structPointerValue(target interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return value, fmt.Errorf("target [%T] is not a non-nil pointer to a struct", target)
+	}
+	return value.Elem(), nil
+}
+
+// isArgField reports whether the field is exported and either carries an 'arg' or 'help' tag, as renamed by options, or is untagged with a type that binds to a flag, so a func or channel field needs no tag to be left alone. A tag of arg:"-" skips the field, as does a 'cmd' tag naming a subcommand.
+func isArgField(field reflect.StructField, options Options) bool {
+	if field.PkgPath != "" || field.Type == commandType {
+		return false
+	}
+	if _, isSubcommand := field.Tag.Lookup(CommandTagKey); isSubcommand {
+		return false
+	}
+	rawArgStr, hasArg := options.argTag(field)
+	if rawArgStr == "-" {
+		return false
+	}
+	_, hasHelp := field.Tag.Lookup(options.helpTagKey())
+	return hasArg || hasHelp || bindsAsFlag(field.Type)
+}
+
+// bindsAsFlag reports whether bindFieldArg binds a field of fieldType, trying it on a scratch flag set.
+func bindsAsFlag(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Struct && !isFlagStructType(fieldType) {
+		return false
+	}
+	variableValue := reflect.New(fieldType).Interface()
+	return bindFieldArg(pflag.NewFlagSet("", pflag.ContinueOnError), fieldType, "", Argument{LongName: "field"}, "", variableValue) == nil
+}
+
+// attachSingleFieldArg is attachCheckedFieldArg holding the lockCommandTree of cmd, for the functions attaching one field.
+func attachSingleFieldArg(cmd *cobra.Command, parmType reflect.Type, variableName string, arg Argument, rawHelp string, variableValue interface{}) error {
+	defer lockCommandTree(cmd)()
+	return attachCheckedFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue)
+}
+
+// attachCheckedFieldArg is attachFieldArg first reporting a long or short name already taken on cmd, which pflag would otherwise panic on deep inside cobra, and then recording the field on the flag so later collisions name it.
+func attachCheckedFieldArg(cmd *cobra.Command, parmType reflect.Type, variableName string, arg Argument, rawHelp string, variableValue interface{}) error {
+	if err := checkDuplicateArg(cmd, parmType, variableName, arg, map[string]string{}, map[string]string{}); err != nil {
+		return err
+	}
+	if err := attachFieldArg(cmd, parmType, variableName, arg, rawHelp, variableValue); err != nil {
+		return err
+	}
+	return argFlags(cmd, arg).SetAnnotation(arg.LongName, fieldAnnotation, []string{parmType.Name() + "." + variableName})
+}
+
+func attachFieldArg(cmd *cobra.Command, parmType reflect.Type, variableName string, arg Argument, rawHelp string, variableValue interface{}) error {
+	if err := bindFieldArg(argFlags(cmd, arg), parmType, variableName, arg, rawHelp, variableValue); err != nil {
+		return err
+	}
+	return configureFlagArg(cmd, arg)
+}
+
+// bindFieldArg adds the flag of arg to flags, bound to the field variableValue points to, with its default value and 'oneof' choices.
+func bindFieldArg(flags *pflag.FlagSet, parmType reflect.Type, variableName string, arg Argument, rawHelp string, variableValue interface{}) error {
+	help := rationalizeHelp(arg, rawHelp)
+	if len(arg.OneOf) > 0 {
+		help = fmt.Sprintf("%v (one of %v)", help, strings.Join(arg.OneOf, "|"))
+	}
+	if flagValue, ok := customValueOf(arg, variableValue); ok {
+		if arg.HasDefaultValue {
+			if err := flagValue.Set(arg.DefaultValue); err != nil {
+				return badDefaultValueError(parmType, variableName, arg)
+			}
+		}
+		flags.VarP(flagValue, arg.LongName, arg.ShortName, help)
+		return bindOneOfArg(flags, parmType, variableName, arg)
+	}
+	switch value := variableValue.(type) {
+	case *string:
+		if arg.Type == "hostport" {
+			hostPortArg := newHostPortValue(value)
+			if arg.HasDefaultValue {
+				if err := hostPortArg.Set(arg.DefaultValue); err != nil {
+					return badDefaultValueError(parmType, variableName, arg)
+				}
+			}
+			flags.VarP(hostPortArg, arg.LongName, arg.ShortName, help)
+			break
+		}
+		flags.StringVarP(value, arg.LongName, arg.ShortName, arg.DefaultValue, help)
+	case *bool:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, booleanStringToValueConverter, false)
+		if err != nil {
+			return err
+		}
+		flags.BoolVarP(value, arg.LongName, arg.ShortName, defaultValue.(bool), help)
+	case *int:
+		if arg.Type == "count" {
+			flags.CountVarP(value, arg.LongName, arg.ShortName, help)
+			break
+		}
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, intStringToValueConverter, 0)
+		if err != nil {
+			return err
+		}
+		flags.IntVarP(value, arg.LongName, arg.ShortName, defaultValue.(int), help)
+	case *int8:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, int8StringToValueConverter, int8(0))
+		if err != nil {
+			return err
+		}
+		flags.Int8VarP(value, arg.LongName, arg.ShortName, defaultValue.(int8), help)
+	case *int16:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, int16StringToValueConverter, int16(0))
+		if err != nil {
+			return err
+		}
+		flags.Int16VarP(value, arg.LongName, arg.ShortName, defaultValue.(int16), help)
+	case *int32:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, int32StringToValueConverter, int32(0))
+		if err != nil {
+			return err
+		}
+		flags.Int32VarP(value, arg.LongName, arg.ShortName, defaultValue.(int32), help)
+	case *int64:
+		if arg.Type == "bytesize" {
+			byteSizeArg := newByteSizeValue(value)
+			if arg.HasDefaultValue {
+				if err := byteSizeArg.Set(arg.DefaultValue); err != nil {
+					return badDefaultValueError(parmType, variableName, arg)
+				}
+				help = fmt.Sprintf("%v (%v bytes)", help, *value)
+			}
+			flags.VarP(byteSizeArg, arg.LongName, arg.ShortName, help)
+			break
+		}
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, int64StringToValueConverter, int64(0))
+		if err != nil {
+			return err
+		}
+		flags.Int64VarP(value, arg.LongName, arg.ShortName, defaultValue.(int64), help)
+	case *uint:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, uintStringToValueConverter, uint(0))
+		if err != nil {
+			return err
+		}
+		flags.UintVarP(value, arg.LongName, arg.ShortName, defaultValue.(uint), help)
+	case *uint8:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, uint8StringToValueConverter, uint8(0))
+		if err != nil {
+			return err
+		}
+		flags.Uint8VarP(value, arg.LongName, arg.ShortName, defaultValue.(uint8), help)
+	case *uint16:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, uint16StringToValueConverter, uint16(0))
+		if err != nil {
+			return err
+		}
+		flags.Uint16VarP(value, arg.LongName, arg.ShortName, defaultValue.(uint16), help)
+	case *uint32:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, uint32StringToValueConverter, uint32(0))
+		if err != nil {
+			return err
+		}
+		flags.Uint32VarP(value, arg.LongName, arg.ShortName, defaultValue.(uint32), help)
+	case *uint64:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, uint64StringToValueConverter, uint64(0))
+		if err != nil {
+			return err
+		}
+		flags.Uint64VarP(value, arg.LongName, arg.ShortName, defaultValue.(uint64), help)
+	case *float32:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, float32StringToValueConverter, float32(0))
+		if err != nil {
+			return err
+		}
+		flags.Float32VarP(value, arg.LongName, arg.ShortName, defaultValue.(float32), help)
+	case *float64:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, float64StringToValueConverter, float64(0))
+		if err != nil {
+			return err
+		}
+		flags.Float64VarP(value, arg.LongName, arg.ShortName, defaultValue.(float64), help)
+	case *time.Duration:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, durationStringToValueConverter, time.Duration(0))
+		if err != nil {
+			return err
+		}
+		flags.DurationVarP(value, arg.LongName, arg.ShortName, defaultValue.(time.Duration), help)
+	case *[]string:
+		if arg.Mode == "slice" {
+			flags.StringSliceVarP(value, arg.LongName, arg.ShortName, splitDefaultValue(arg), help)
+			break
+		}
+		flags.StringArrayVarP(value, arg.LongName, arg.ShortName, splitDefaultValue(arg), help)
+	case *[]int:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, intStringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]int, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(int)
+		}
+		flags.IntSliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *[]int64:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, int64StringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]int64, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(int64)
+		}
+		flags.Int64SliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *[]float32:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, float32StringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]float32, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(float32)
+		}
+		flags.Float32SliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *[]float64:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, float64StringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]float64, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(float64)
+		}
+		flags.Float64SliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *[]time.Duration:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, durationStringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]time.Duration, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(time.Duration)
+		}
+		flags.DurationSliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *[]net.IP:
+		defaultValues, err := convertDefaultValues(arg, parmType, variableName, ipStringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make([]net.IP, len(defaultValues))
+		for index, item := range defaultValues {
+			defaultValue[index] = item.(net.IP)
+		}
+		flags.IPSliceVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *map[string]string:
+		defaultValues, err := convertDefaultMap(arg, parmType, variableName, stringStringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make(map[string]string, len(defaultValues))
+		for key, item := range defaultValues {
+			defaultValue[key] = item.(string)
+		}
+		flags.StringToStringVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *map[string]int:
+		defaultValues, err := convertDefaultMap(arg, parmType, variableName, intStringToValueConverter)
+		if err != nil {
+			return err
+		}
+		defaultValue := make(map[string]int, len(defaultValues))
+		for key, item := range defaultValues {
+			defaultValue[key] = item.(int)
+		}
+		flags.StringToIntVarP(value, arg.LongName, arg.ShortName, defaultValue, help)
+	case *net.IP:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, ipStringToValueConverter, net.IP(nil))
+		if err != nil {
+			return err
+		}
+		flags.IPVarP(value, arg.LongName, arg.ShortName, defaultValue.(net.IP), help)
+	case *net.IPNet:
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, ipNetStringToValueConverter, net.IPNet{})
+		if err != nil {
+			return err
+		}
+		flags.IPNetVarP(value, arg.LongName, arg.ShortName, defaultValue.(net.IPNet), help)
+	case *[]byte:
+		if arg.Encoding == "base64" {
+			defaultValue, err := convertDefaultValue(arg, parmType, variableName, bytesBase64StringToValueConverter, []byte(nil))
+			if err != nil {
+				return err
+			}
+			flags.BytesBase64VarP(value, arg.LongName, arg.ShortName, defaultValue.([]byte), help)
+			break
+		}
+		defaultValue, err := convertDefaultValue(arg, parmType, variableName, bytesHexStringToValueConverter, []byte(nil))
+		if err != nil {
+			return err
+		}
+		flags.BytesHexVarP(value, arg.LongName, arg.ShortName, defaultValue.([]byte), help)
+	case *os.FileMode:
+		fileModeArg := newFileModeValue(value)
+		if arg.HasDefaultValue {
+			if err := fileModeArg.Set(arg.DefaultValue); err != nil {
+				return badDefaultValueError(parmType, variableName, arg)
+			}
+		}
+		flags.VarP(fileModeArg, arg.LongName, arg.ShortName, help)
+	case *time.Time:
+		timeArg := newTimeValue(value, arg.Layout)
+		if arg.HasDefaultValue {
+			if err := timeArg.Set(arg.DefaultValue); err != nil {
+				return badDefaultValueError(parmType, variableName, arg)
+			}
+		}
+		flags.VarP(timeArg, arg.LongName, arg.ShortName, help)
+	case **url.URL:
+		urlArg := newURLValue(value, arg.Schemes)
+		if arg.HasDefaultValue {
+			if err := urlArg.Set(arg.DefaultValue); err != nil {
+				return badDefaultValueError(parmType, variableName, arg)
+			}
+		}
+		flags.VarP(urlArg, arg.LongName, arg.ShortName, help)
+	default:
+		textArg, ok := textValueOf(variableValue)
+		if !ok {
+			return newArgError(ErrUnsupportedType, parmType, variableName, "", "", "field %v.%v has type %T which is not supported", parmType.Name(), variableName, variableValue)
+		}
+		if arg.HasDefaultValue {
+			if err := textArg.Set(arg.DefaultValue); err != nil {
+				return badDefaultValueError(parmType, variableName, arg)
+			}
+		}
+		flags.VarP(textArg, arg.LongName, arg.ShortName, help)
+	}
+	return bindOneOfArg(flags, parmType, variableName, arg)
+}
+
+// Unmarshal populates the arg fields of the struct pointed to by target from the values of the flags parsed by cmd.
+func Unmarshal(cmd *cobra.Command, target interface{}) error {
+	return unmarshal(cmd, target, Options{})
+}
+
+func unmarshal(cmd *cobra.Command, target interface{}, options Options) error {
+	structValue, err := structPointerValue(target)
+	if err != nil {
+		return err
+	}
+	fields, err := structArgs(structValue.Type(), options)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			return inStruct(err, field.parent)
+		}
+		flags := flagSetWith(cmd, arg.LongName)
+		if arg.Positional {
+			flags = positionalFlags(cmd)
+		}
+		if flags == nil {
+			return fmt.Errorf("field %v.%v has no flag named [%v] attached to command %v", field.parent.Name(), field.Name, arg.LongName, cmd.Name())
+		}
+		variableValue := structValue.FieldByIndex(field.Index).Addr().Interface()
+		if err = unmarshalFieldArg(flags, field.parent, field.Name, arg, variableValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unmarshalFieldArg(flags *pflag.FlagSet, parmType reflect.Type, variableName string, arg Argument, variableValue interface{}) (err error) {
+	if flagValue, ok := customValueOf(arg, variableValue); ok {
+		return copyPflagValue(flags.Lookup(arg.LongName).Value, flagValue)
+	}
+	// the custom value types are asserted on the bound value under any 'indirect' or 'oneof' wrappers
+	boundValue := unwrapValue(flags.Lookup(arg.LongName).Value)
+	switch value := variableValue.(type) {
+	case *string:
+		if hostPortArg, ok := boundValue.(*hostPortValue); ok {
+			*value = hostPortArg.String()
+		} else {
+			*value, err = flags.GetString(arg.LongName)
+		}
+	case *bool:
+		*value, err = flags.GetBool(arg.LongName)
+	case *int:
+		if flags.Lookup(arg.LongName).Value.Type() == "count" {
+			*value, err = flags.GetCount(arg.LongName)
+		} else {
+			*value, err = flags.GetInt(arg.LongName)
+		}
+	case *int8:
+		*value, err = flags.GetInt8(arg.LongName)
+	case *int16:
+		*value, err = flags.GetInt16(arg.LongName)
+	case *int32:
+		*value, err = flags.GetInt32(arg.LongName)
+	case *int64:
+		if byteSizeArg, ok := boundValue.(*byteSizeValue); ok {
+			*value = *byteSizeArg.value
+		} else {
+			*value, err = flags.GetInt64(arg.LongName)
+		}
+	case *uint:
+		*value, err = flags.GetUint(arg.LongName)
+	case *uint8:
+		*value, err = flags.GetUint8(arg.LongName)
+	case *uint16:
+		*value, err = flags.GetUint16(arg.LongName)
+	case *uint32:
+		*value, err = flags.GetUint32(arg.LongName)
+	case *uint64:
+		*value, err = flags.GetUint64(arg.LongName)
+	case *float32:
+		*value, err = flags.GetFloat32(arg.LongName)
+	case *float64:
+		*value, err = flags.GetFloat64(arg.LongName)
+	case *time.Duration:
+		*value, err = flags.GetDuration(arg.LongName)
+	case *[]string:
+		if flags.Lookup(arg.LongName).Value.Type() == "stringSlice" {
+			*value, err = flags.GetStringSlice(arg.LongName)
+		} else {
+			*value, err = flags.GetStringArray(arg.LongName)
+		}
+	case *[]int:
+		*value, err = flags.GetIntSlice(arg.LongName)
+	case *[]int64:
+		*value, err = flags.GetInt64Slice(arg.LongName)
+	case *[]float32:
+		*value, err = flags.GetFloat32Slice(arg.LongName)
+	case *[]float64:
+		*value, err = flags.GetFloat64Slice(arg.LongName)
+	case *[]time.Duration:
+		*value, err = flags.GetDurationSlice(arg.LongName)
+	case *[]net.IP:
+		*value, err = flags.GetIPSlice(arg.LongName)
+	case *map[string]string:
+		*value, err = flags.GetStringToString(arg.LongName)
+	case *map[string]int:
+		*value, err = flags.GetStringToInt(arg.LongName)
+	case *net.IP:
+		// pflag cannot convert an unset IP or IPNet back from its "<nil>" string form
+		if boundValue.String() == "<nil>" {
+			*value = nil
+		} else {
+			*value, err = flags.GetIP(arg.LongName)
+		}
+	case *net.IPNet:
+		if boundValue.String() == "<nil>" {
+			*value = net.IPNet{}
+		} else {
+			*value, err = flags.GetIPNet(arg.LongName)
+		}
+	case *[]byte:
+		if flags.Lookup(arg.LongName).Value.Type() == "bytesBase64" {
+			*value, err = flags.GetBytesBase64(arg.LongName)
+		} else {
+			*value, err = flags.GetBytesHex(arg.LongName)
+		}
+	case *os.FileMode:
+		fileModeArg, ok := boundValue.(*fileModeValue)
+		if !ok {
+			return fmt.Errorf("flag [%v] for field %v.%v is not a file mode flag", arg.LongName, parmType.Name(), variableName)
+		}
+		*value = *fileModeArg.value
+	case *time.Time:
+		timeArg, ok := boundValue.(*timeValue)
+		if !ok {
+			return fmt.Errorf("flag [%v] for field %v.%v is not a time flag", arg.LongName, parmType.Name(), variableName)
+		}
+		*value = *timeArg.value
+	case **url.URL:
+		urlArg, ok := boundValue.(*urlValue)
+		if !ok {
+			return fmt.Errorf("flag [%v] for field %v.%v is not a url flag", arg.LongName, parmType.Name(), variableName)
+		}
+		*value = *urlArg.value
+	default:
+		textArg, ok := textValueOf(variableValue)
+		if !ok {
+			return newArgError(ErrUnsupportedType, parmType, variableName, "", "", "field %v.%v has type %T which is not supported", parmType.Name(), variableName, variableValue)
+		}
+		return copyPflagValue(flags.Lookup(arg.LongName).Value, textArg)
+	}
+	return err
+}
+
+// pflagValueOf returns the field as a pflag.Value when either the field or its address implements pflag.Value. A nil pointer field is allocated.
+func pflagValueOf(variableValue interface{}) (pflag.Value, bool) {
+	if flagValue, ok := variableValue.(pflag.Value); ok {
+		return flagValue, true
+	}
+	fieldValue := reflect.ValueOf(variableValue).Elem()
+	if fieldValue.Kind() != reflect.Ptr || !fieldValue.Type().Implements(pflagValueType) {
+		return nil, false
+	}
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+	}
+	return fieldValue.Interface().(pflag.Value), true
+}
+
+var pflagValueType = reflect.TypeOf((*pflag.Value)(nil)).Elem()
+
+// copyPflagValue copies source into target, directly when both share a type and through String/Set otherwise.
+func copyPflagValue(source, target pflag.Value) error {
+	source = unwrapValue(source)
+	if sourceField, ok := source.(fieldPointerValue); ok {
+		if targetField, ok := target.(fieldPointerValue); ok && sourceField.fieldPointer().Type() == targetField.fieldPointer().Type() {
+			copyPointedValue(sourceField.fieldPointer(), targetField.fieldPointer())
+			return nil
+		}
+	} else if sourceValue, targetValue := reflect.ValueOf(source), reflect.ValueOf(target); sourceValue.Type() == targetValue.Type() && sourceValue.Kind() == reflect.Ptr {
+		copyPointedValue(sourceValue, targetValue)
+		return nil
+	}
+	return target.Set(source.String())
+}
+
+// wrappedValue is a pflag.Value, such as oneOfValue, that adds checks around another.
+type wrappedValue interface {
+	unwrap() pflag.Value
+}
+
+// unwrapValue returns the innermost pflag.Value under any wrappedValue layers.
+func unwrapValue(value pflag.Value) pflag.Value {
+	for {
+		wrapped, ok := value.(wrappedValue)
+		if !ok {
+			return value
+		}
+		value = wrapped.unwrap()
+	}
+}
+
+func copyPointedValue(source, target reflect.Value) {
+	if source.Pointer() != target.Pointer() {
+		target.Elem().Set(source.Elem())
+	}
+}
+
+// customValueOf returns a pflag.Value bound to the field for fields tagged 'format=json', json.RawMessage fields, types with a registered converter and fields implementing pflag.Value.
+func customValueOf(arg Argument, variableValue interface{}) (pflag.Value, bool) {
+	if jsonArg, ok := jsonValueOf(arg, variableValue); ok {
+		return jsonArg, true
+	}
+	if converterArg, ok := converterValueOf(variableValue); ok {
+		return converterArg, true
+	}
+	return pflagValueOf(variableValue)
+}
+
+// argFlags returns the flag set the argument is attached to: the persistent flags, inherited by subcommands, for 'persistent=true' and the local flags otherwise.
+func argFlags(cmd *cobra.Command, arg Argument) *pflag.FlagSet {
+	if arg.Persistent {
+		return cmd.PersistentFlags()
+	}
+	return cmd.Flags()
+}
+
+// flagSetWith returns the first of the local, persistent and inherited flag sets of cmd that has the named flag, or nil when none does.
+func flagSetWith(cmd *cobra.Command, name string) *pflag.FlagSet {
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags(), cmd.InheritedFlags()} {
+		if flags.Lookup(name) != nil {
+			return flags
+		}
+	}
+	return nil
+}
+
+// bindOneOfArg restricts a bound flag to the 'oneof' choices, described by the 'choicehelp' tag, as in `arg:"oneof=json|text,choicehelp='Machine readable|Human readable'"`.
+func bindOneOfArg(flags *pflag.FlagSet, parmType reflect.Type, variableName string, arg Argument) error {
+	if len(arg.OneOf) == 0 {
+		return nil
+	}
+	flag := flags.Lookup(arg.LongName)
+	if arg.HasDefaultValue && !containsString(arg.OneOf, flag.Value.String()) {
+		return newArgError(ErrBadDefaultValue, parmType, variableName, "defaultvalue", arg.DefaultValue, "field %v.%v default value [%v] is not one of %v", parmType.Name(), variableName, arg.DefaultValue, strings.Join(arg.OneOf, "|"))
+	}
+	flag.Value = &oneOfValue{Value: flag.Value, choices: arg.OneOf, descriptions: arg.ChoiceHelp}
+	return nil
+}
@@ -0,0 +1,196 @@
+package cobraargs
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type untaggedArgs struct {
+	Name     string
+	Count    int
+	Debug    bool
+	Timeout  time.Duration
+	Since    time.Time
+	Tags     []string
+	Tagged   string `arg:"shortname=t,defaultvalue=x"`
+	Helped   string `help:"has help"`
+	Skipped  string `arg:"-"`
+	OnDone   func()
+	Events   chan string
+	Nested   struct{ Inner string }
+	Sub      serveParams `cmd:"serve"`
+	internal string
+}
+
+func TestAttachStructArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		want     bool
+		defValue string
+	}{
+		{name: "untagged string", flag: "name", want: true},
+		{name: "untagged int", flag: "count", want: true, defValue: "0"},
+		{name: "untagged bool", flag: "debug", want: true, defValue: "false"},
+		{name: "untagged duration", flag: "timeout", want: true, defValue: "0s"},
+		{name: "untagged time", flag: "since", want: true},
+		{name: "untagged slice", flag: "tags", want: true, defValue: "[]"},
+		{name: "arg tag", flag: "tagged", want: true, defValue: "x"},
+		{name: "help tag", flag: "helped", want: true},
+		{name: "skipped", flag: "skipped"},
+		{name: "func", flag: "onDone"},
+		{name: "chan", flag: "events"},
+		{name: "untagged struct", flag: "nested"},
+		{name: "untagged struct field", flag: "inner"},
+		{name: "subcommand", flag: "sub"},
+		{name: "unexported", flag: "internal"},
+	}
+	var args untaggedArgs
+	cmd := &cobra.Command{Use: "app"}
+	if err := AttachStructArgs(cmd, &args); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flag := cmd.Flags().Lookup(test.flag)
+			if (flag != nil) != test.want {
+				t.Fatalf("flag --%v attached %v, want %v", test.flag, flag != nil, test.want)
+			}
+			if flag != nil && flag.DefValue != test.defValue {
+				t.Errorf("flag --%v default %q, want %q", test.flag, flag.DefValue, test.defValue)
+			}
+		})
+	}
+	cmd.Run = func(*cobra.Command, []string) {}
+	cmd.SetArgs([]string{"--name", "al", "--count", "2", "--debug", "--timeout", "5s", "--tags", "a", "--tags", "b", "-t", "y"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if args.Name != "al" || args.Count != 2 || !args.Debug || args.Timeout != 5*time.Second || len(args.Tags) != 2 || args.Tagged != "y" {
+		t.Errorf("got %+v", args)
+	}
+}
+
+// TestAttachConcurrent attaches to the commands of one tree, and registers tag names, from many goroutines at once; run it with -race.
+func TestAttachConcurrent(t *testing.T) {
+	type rootArgs struct {
+		Verbose bool `arg:"persistent,shortname=v"`
+	}
+	type subArgs struct {
+		Name  string `arg:"shortname=n,defaultvalue=bob,validate=test-concurrent-name"`
+		Count int    `arg:"min=0,max=10"`
+	}
+	RegisterValidator("test-concurrent-name", func(string, string) error { return nil })
+	tests := []struct {
+		name     string
+		commands int
+		register bool
+	}{
+		{name: "siblings", commands: 16},
+		{name: "siblings while registering", commands: 16, register: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "app"}
+			subs := make([]*cobra.Command, test.commands)
+			for index := range subs {
+				subs[index] = &cobra.Command{Use: fmt.Sprintf("sub%v", index), Run: func(*cobra.Command, []string) {}}
+				root.AddCommand(subs[index])
+			}
+			var root0 rootArgs
+			targets := make([]subArgs, test.commands)
+			errs := make(chan error, 2*test.commands+1)
+			var wait sync.WaitGroup
+			wait.Add(1)
+			go func() {
+				defer wait.Done()
+				errs <- AttachStructArgs(root, &root0)
+			}()
+			for index := range subs {
+				wait.Add(1)
+				go func(index int) {
+					defer wait.Done()
+					errs <- AttachStructArgs(subs[index], &targets[index])
+				}(index)
+				if test.register {
+					wait.Add(1)
+					go func(index int) {
+						defer wait.Done()
+						RegisterCompleter(fmt.Sprintf("test-concurrent-%v", index), func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+							return nil, cobra.ShellCompDirectiveDefault
+						})
+						RegisterValidator(fmt.Sprintf("test-concurrent-%v", index), func(string, string) error { return nil })
+					}(index)
+				}
+			}
+			wait.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			for index, sub := range subs {
+				if sub.Flags().Lookup("name") == nil || sub.Flags().Lookup("count") == nil {
+					t.Errorf("command %v is missing its flags", sub.Name())
+				}
+				root.SetArgs([]string{sub.Name(), "-v", "-n", "al", "--count", "3"})
+				if err := root.Execute(); err != nil {
+					t.Fatal(err)
+				}
+				if !root0.Verbose || targets[index].Name != "al" || targets[index].Count != 3 {
+					t.Errorf("command %v got %+v %+v", sub.Name(), root0, targets[index])
+				}
+			}
+			attachLocksLock.Lock()
+			defer attachLocksLock.Unlock()
+			if len(attachLocks) != 0 {
+				t.Errorf("%v command locks left after attaching", len(attachLocks))
+			}
+		})
+	}
+}
+
+func TestLockCommandTree(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	sub := &cobra.Command{Use: "sub"}
+	other := &cobra.Command{Use: "other"}
+	leaf := &cobra.Command{Use: "leaf"}
+	root.AddCommand(sub, other)
+	sub.AddCommand(leaf)
+	tests := []struct {
+		name      string
+		lock      []*cobra.Command
+		wantLocks int
+	}{
+		{name: "root", lock: []*cobra.Command{root}, wantLocks: 1},
+		{name: "leaf", lock: []*cobra.Command{leaf}, wantLocks: 3},
+		{name: "cousins share ancestors", lock: []*cobra.Command{leaf, other}, wantLocks: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var unlocks []func()
+			for _, cmd := range test.lock {
+				unlocks = append(unlocks, lockCommandTree(cmd))
+			}
+			attachLocksLock.Lock()
+			locks := len(attachLocks)
+			attachLocksLock.Unlock()
+			if locks != test.wantLocks {
+				t.Errorf("%v locks while attaching, want %v", locks, test.wantLocks)
+			}
+			for _, unlock := range unlocks {
+				unlock()
+			}
+			attachLocksLock.Lock()
+			defer attachLocksLock.Unlock()
+			if len(attachLocks) != 0 {
+				t.Errorf("%v locks left after attaching", len(attachLocks))
+			}
+		})
+	}
+}
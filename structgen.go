@@ -0,0 +1,153 @@
+package cobraargs
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// structGenTypes maps pflag value types to the field type binding them and the tag item selecting that flag type, if the field type alone does not.
+var structGenTypes = map[string][2]string{
+	"string":         {"string", ""},
+	"bool":           {"bool", ""},
+	"int":            {"int", ""},
+	"int8":           {"int8", ""},
+	"int16":          {"int16", ""},
+	"int32":          {"int32", ""},
+	"int64":          {"int64", ""},
+	"uint":           {"uint", ""},
+	"uint8":          {"uint8", ""},
+	"uint16":         {"uint16", ""},
+	"uint32":         {"uint32", ""},
+	"uint64":         {"uint64", ""},
+	"float32":        {"float32", ""},
+	"float64":        {"float64", ""},
+	"duration":       {"time.Duration", ""},
+	"count":          {"int", "type=count"},
+	"bytesize":       {"int64", "type=bytesize"},
+	"hostport":       {"string", "type=hostport"},
+	"stringArray":    {"[]string", ""},
+	"stringSlice":    {"[]string", "mode=slice"},
+	"intSlice":       {"[]int", ""},
+	"int64Slice":     {"[]int64", ""},
+	"float32Slice":   {"[]float32", ""},
+	"float64Slice":   {"[]float64", ""},
+	"durationSlice":  {"[]time.Duration", ""},
+	"ipSlice":        {"[]net.IP", ""},
+	"stringToString": {"map[string]string", ""},
+	"stringToInt":    {"map[string]int", ""},
+	"ip":             {"net.IP", ""},
+	"ipNet":          {"net.IPNet", ""},
+	"bytesHex":       {"[]byte", ""},
+	"bytesBase64":    {"[]byte", "encoding=base64"},
+	"filemode":       {"os.FileMode", ""},
+	"time":           {"time.Time", ""},
+	"url":            {"*url.URL", ""},
+	"json":           {"json.RawMessage", ""},
+}
+
+// GenStructSource writes Go source for an arg struct named structName equivalent to the flags cmd declares, easing the move of an existing cobra command to cobraargs: each flag becomes a field of the matching type with its long name, short name, default, required, hidden, deprecated, persistent, placeholder and file completion as tags and its usage as help. Flags of a type cobraargs cannot bind are written as string fields with a comment naming the type. The source needs the imports of the types it uses, such as time and net.
+func GenStructSource(w io.Writer, cmd *cobra.Command, structName string) error {
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// %v holds the flags of the %v command.\ntype %v struct {\n", structName, cmd.Name(), structName)
+	taken := map[string]bool{}
+	specFlags(cmd, func(flag *pflag.Flag, persistent bool) {
+		if flag.Annotations[cobra.FlagSetByCobraAnnotation] != nil {
+			return
+		}
+		fieldName := schemaFieldName(flag.Name)
+		for taken[fieldName] {
+			fieldName += "_"
+		}
+		taken[fieldName] = true
+		fieldType, comment := "string", ""
+		goType, known := structGenTypes[flag.Value.Type()]
+		if known {
+			fieldType = goType[0]
+		} else {
+			comment = " // flag type " + flag.Value.Type() + " has no cobraargs equivalent"
+		}
+		items := structGenTagItems(flag, fieldName, goType[1], persistent)
+		help := flag.Usage
+		if strings.Contains(flag.Usage, "`") {
+			var placeholder string
+			placeholder, help = pflag.UnquoteUsage(flag)
+			items = append(items, "placeholder="+escapeTagValue(placeholder))
+		}
+		tag := "arg:" + strconv.Quote(strings.Join(items, ","))
+		if help != "" {
+			tag += " help:" + strconv.Quote(help)
+		}
+		fmt.Fprintf(&source, "\t%v %v `%v`%v\n", fieldName, fieldType, tag, comment)
+	})
+	source.WriteString("}\n")
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// structGenTagItems writes the arg tag items reproducing flag on a field named fieldName.
+func structGenTagItems(flag *pflag.Flag, fieldName, typeItem string, persistent bool) []string {
+	var items []string
+	if longNameOf(fieldName, DefaultNameStyle) != flag.Name {
+		items = append(items, "longname="+escapeTagValue(flag.Name))
+	}
+	if flag.Shorthand != "" {
+		items = append(items, "shortname="+flag.Shorthand)
+	}
+	if typeItem != "" {
+		items = append(items, typeItem)
+	}
+	if defaultValue, has := structGenDefault(flag); has {
+		items = append(items, "defaultvalue="+escapeTagValue(defaultValue))
+	}
+	if flag.NoOptDefVal != "" && !(flag.Value.Type() == "bool" && flag.NoOptDefVal == "true") && flag.Value.Type() != "count" {
+		items = append(items, "nooptdefault="+escapeTagValue(flag.NoOptDefVal))
+	}
+	if flagRequired(flag) {
+		items = append(items, "required")
+	}
+	if persistent {
+		items = append(items, "persistent")
+	}
+	if flag.Hidden {
+		items = append(items, "hidden")
+	}
+	if flag.Deprecated != "" {
+		items = append(items, "deprecated="+escapeTagValue(flag.Deprecated))
+	}
+	if flag.ShorthandDeprecated != "" {
+		items = append(items, "shorthanddeprecated="+escapeTagValue(flag.ShorthandDeprecated))
+	}
+	if extensions, has := flag.Annotations[cobra.BashCompFilenameExt]; has {
+		complete := "files"
+		if len(extensions) > 0 {
+			complete += ":" + strings.Join(extensions, "|")
+		}
+		items = append(items, "complete="+escapeTagValue(complete))
+	} else if _, has := flag.Annotations[cobra.BashCompSubdirsInDir]; has {
+		items = append(items, "complete=dirs")
+	}
+	return items
+}
+
+// structGenDefault is the 'defaultvalue' of flag, with list items separated by DefaultValueOnListSeparator, unless it is the zero value.
+func structGenDefault(flag *pflag.Flag) (string, bool) {
+	switch flag.DefValue {
+	case "", "0", "false", "[]", "0s", "<nil>":
+		return "", false
+	}
+	if strings.HasSuffix(flag.Value.Type(), "Slice") || flag.Value.Type() == "stringArray" || strings.HasPrefix(flag.Value.Type(), "stringTo") {
+		return strings.ReplaceAll(strings.Trim(flag.DefValue, "[]"), ",", DefaultValueOnListSeparator), true
+	}
+	return flag.DefValue, true
+}
@@ -0,0 +1,54 @@
+package cobraargs
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGenStructSource(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "app"}
+	rootCmd.PersistentFlags().CountP("verbose", "v", "more output")
+	cmd := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+	rootCmd.AddCommand(cmd)
+	cmd.Flags().StringP("listen-addr", "l", ":8080", "address to listen on (`HOST:PORT`)")
+	cmd.Flags().Int("workers", 4, "worker count")
+	cmd.Flags().Duration("timeout", 30*time.Second, "request timeout")
+	cmd.Flags().StringSlice("tags", []string{"a", "b"}, `tags, "quoted"`)
+	cmd.Flags().IP("bind", net.ParseIP("127.0.0.1"), "")
+	cmd.Flags().String("token", "", "API token")
+	cmd.Flags().Bool("old", false, "old behavior")
+	cmd.Flags().Var(&hostPortValue{value: new(string)}, "upstream", "upstream server")
+	if err := cmd.MarkFlagRequired("token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Flags().MarkDeprecated("old", "use --new"); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := GenStructSource(&out, cmd, "ServeArgs"); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "structgen.go.golden", out.Bytes())
+	if bytes.Contains(out.Bytes(), []byte("Verbose")) {
+		t.Error("generated struct has the --verbose flag inherited from the parent command")
+	}
+	source := "package gen\n\nimport (\n\t\"net\"\n\t\"time\"\n)\n\n" + out.String()
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "gen.go", source, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, source)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+	if _, err := config.Check("gen", fileSet, []*ast.File{file}, nil); err != nil {
+		t.Errorf("generated source does not compile: %v\n%s", err, source)
+	}
+}
@@ -0,0 +1,123 @@
+package cobraargs
+
+import (
+	"errors"
+	"strings"
+)
+
+type tagItem struct {
+	Name     string
+	Value    string
+	HasValue bool
+	Raw      string
+}
+
+var (
+	errUnterminatedQuote = errors.New("unterminated quote")
+	errTrailingEscape    = errors.New("trailing backslash")
+)
+
+// splitArgTag splits an arg tag into its comma separated key=value items. A value may be wrapped in single quotes so that commas and equals signs are taken literally ('a=b,c=d'), and outside or inside quotes a backslash escapes the next character (a\,b or 'it\'s'); inside a Go struct tag literal the backslash itself must be doubled, as in `arg:"defaultvalue=a\\,b"`.
+func splitArgTag(rawArgStr string) ([]tagItem, error) {
+	items := make([]tagItem, 0, strings.Count(rawArgStr, ",")+1)
+	scanner := tagScanner{raw: rawArgStr}
+	for {
+		item, ok, err := scanner.next()
+		if err != nil {
+			return items, err
+		}
+		if !ok {
+			return items, nil
+		}
+		items = append(items, item)
+	}
+}
+
+// tagScanner reads the items of an arg tag one at a time in a single pass, as splitArgTag splits them. The name and value of an item without quotes or escapes are slices of the tag, so the common tag is read without allocating.
+type tagScanner struct {
+	raw  string
+	pos  int
+	done bool
+}
+
+// next returns the next item of the tag, or false once every item has been read.
+func (scanner *tagScanner) next() (tagItem, bool, error) {
+	if scanner.done {
+		return tagItem{}, false, nil
+	}
+	raw := scanner.raw
+	start, equals, end := scanner.pos, -1, len(raw)
+	inQuote, decode := false, false
+scan:
+	for i := start; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(raw) {
+				scanner.done = true
+				return tagItem{}, false, errTrailingEscape
+			}
+			i++
+			decode = true
+		case inQuote:
+			inQuote = c != '\''
+		case c == '\'' && equals >= 0:
+			inQuote, decode = true, true
+		case c == '=' && equals < 0:
+			equals = i
+		case c == ',':
+			end = i
+			break scan
+		}
+	}
+	if inQuote {
+		scanner.done = true
+		return tagItem{}, false, errUnterminatedQuote
+	}
+	scanner.pos = end + 1
+	scanner.done = end == len(raw)
+	item := tagItem{Raw: raw[start:end]}
+	switch {
+	case decode:
+		item.Name, item.Value, item.HasValue = decodeTagItem(item.Raw)
+	case equals < 0:
+		item.Name = item.Raw
+	default:
+		item.Name, item.Value, item.HasValue = raw[start:equals], raw[equals+1:end], true
+	}
+	return item, true, nil
+}
+
+// decodeTagItem reads the name and value of a tag item holding quotes or escapes, already checked to be well formed by tagScanner. Both are decoded into one buffer, so the item allocates once.
+func decodeTagItem(raw string) (name, value string, hasValue bool) {
+	var text strings.Builder
+	text.Grow(len(raw))
+	nameEnd := 0
+	inQuote := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\\':
+			i++
+			text.WriteByte(raw[i])
+		case inQuote:
+			if c == '\'' {
+				inQuote = false
+			} else {
+				text.WriteByte(c)
+			}
+		case c == '\'' && hasValue:
+			inQuote = true
+		case c == '=' && !hasValue:
+			nameEnd = text.Len()
+			hasValue = true
+		default:
+			text.WriteByte(c)
+		}
+	}
+	decoded := text.String()
+	if hasValue {
+		return decoded[:nameEnd], decoded[nameEnd:], true
+	}
+	return decoded, "", false
+}
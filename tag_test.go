@@ -0,0 +1,104 @@
+package cobraargs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []tagItem
+		wantErr error
+	}{
+		{name: "empty", raw: "", want: []tagItem{{}}},
+		{name: "bare key", raw: "required", want: []tagItem{{Name: "required", Raw: "required"}}},
+		{name: "key values", raw: "shortname=n,defaultvalue=bob", want: []tagItem{
+			{Name: "shortname", Value: "n", HasValue: true, Raw: "shortname=n"},
+			{Name: "defaultvalue", Value: "bob", HasValue: true, Raw: "defaultvalue=bob"},
+		}},
+		{name: "empty value", raw: "defaultvalue=,required", want: []tagItem{
+			{Name: "defaultvalue", HasValue: true, Raw: "defaultvalue="},
+			{Name: "required", Raw: "required"},
+		}},
+		{name: "equals in value", raw: "defaultvalue=a=b", want: []tagItem{{Name: "defaultvalue", Value: "a=b", HasValue: true, Raw: "defaultvalue=a=b"}}},
+		{name: "trailing comma", raw: "required,", want: []tagItem{{Name: "required", Raw: "required"}, {}}},
+		{name: "quoted value", raw: "defaultvalue='a,b=c'", want: []tagItem{{Name: "defaultvalue", Value: "a,b=c", HasValue: true, Raw: "defaultvalue='a,b=c'"}}},
+		{name: "quoted empty value", raw: "defaultvalue='',hidden", want: []tagItem{
+			{Name: "defaultvalue", HasValue: true, Raw: "defaultvalue=''"},
+			{Name: "hidden", Raw: "hidden"},
+		}},
+		{name: "quoted part of value", raw: "defaultvalue=x'y,z'w", want: []tagItem{{Name: "defaultvalue", Value: "xy,zw", HasValue: true, Raw: "defaultvalue=x'y,z'w"}}},
+		{name: "quote in name", raw: "it's=v", want: []tagItem{{Name: "it's", Value: "v", HasValue: true, Raw: "it's=v"}}},
+		{name: "escaped comma", raw: `defaultvalue=a\,b`, want: []tagItem{{Name: "defaultvalue", Value: "a,b", HasValue: true, Raw: `defaultvalue=a\,b`}}},
+		{name: "escaped quote in quotes", raw: `defaultvalue='it\'s'`, want: []tagItem{{Name: "defaultvalue", Value: "it's", HasValue: true, Raw: `defaultvalue='it\'s'`}}},
+		{name: "escaped backslash", raw: `defaultvalue=a\\,hidden`, want: []tagItem{
+			{Name: "defaultvalue", Value: `a\`, HasValue: true, Raw: `defaultvalue=a\\`},
+			{Name: "hidden", Raw: "hidden"},
+		}},
+		{name: "escaped equals in name", raw: `a\=b=c`, want: []tagItem{{Name: "a=b", Value: "c", HasValue: true, Raw: `a\=b=c`}}},
+		{name: "unterminated quote", raw: "defaultvalue='a,b", wantErr: errUnterminatedQuote},
+		{name: "trailing backslash", raw: `defaultvalue=a\`, wantErr: errTrailingEscape},
+		{name: "error after items", raw: `required,defaultvalue='a`, want: []tagItem{{Name: "required", Raw: "required"}}, wantErr: errUnterminatedQuote},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := splitArgTag(test.raw)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("splitArgTag(%q) error %v, want %v", test.raw, err, test.wantErr)
+			}
+			if len(items) != len(test.want) || (len(items) > 0 && !reflect.DeepEqual(items, test.want)) {
+				t.Errorf("splitArgTag(%q) = %+v, want %+v", test.raw, items, test.want)
+			}
+		})
+	}
+}
+
+func TestTagScannerAllocs(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want float64
+	}{
+		{name: "plain", raw: "shortname=n,defaultvalue=bob,required,env=APP_NAME", want: 0},
+		{name: "quoted", raw: "defaultvalue='a,b',required", want: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				scanner := tagScanner{raw: test.raw}
+				for {
+					if _, ok, _ := scanner.next(); !ok {
+						return
+					}
+				}
+			})
+			if allocs > test.want {
+				t.Errorf("scanning %q allocates %v times, want at most %v", test.raw, allocs, test.want)
+			}
+		})
+	}
+}
+
+type benchmarkTagArgs struct {
+	Plain  string `arg:"shortname=n,defaultvalue=bob,required,env=APP_NAME|LEGACY_NAME,placeholder=NAME"`
+	Quoted string `arg:"shortname=q,defaultvalue='a,b=c',pattern='^[a-z]+\\,$'"`
+}
+
+func BenchmarkParseArgTag(b *testing.B) {
+	structType := reflect.TypeOf(benchmarkTagArgs{})
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		rawArgStr := field.Tag.Get("arg")
+		b.Run(field.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := parseArgTag(field, rawArgStr, DefaultNameStyle); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
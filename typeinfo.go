@@ -0,0 +1,111 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// TypeInfo is everything the struct functions read from the tags of an arg struct, parsed once by ParseType for tooling to inspect and for attaching the struct to many commands.
+type TypeInfo struct {
+	// Type is the struct type parsed.
+	Type reflect.Type
+	// Fields are its arg fields in declaration order, those of nested structs in place of the struct field.
+	Fields []FieldInfo
+	// StructValidator reports whether a pointer to Type implements StructValidator.
+	StructValidator bool
+
+	options Options
+}
+
+// FieldInfo is one arg field of a TypeInfo.
+type FieldInfo struct {
+	// Name is the Go field name and Path its dotted path from the outer struct, the key of Options.Overrides.
+	Name string
+	Path string
+	// Index is the index sequence of the field for reflect.Value.FieldByIndex on a value of TypeInfo.Type.
+	Index []int
+	// Struct is the struct type declaring the field.
+	Struct reflect.Type
+	// Argument is the parsed tag of the field, with the long name and aliases prefixed by any nested 'prefix'.
+	Argument Argument
+	// Help is the help tag of the field, without the MANDATORY or optional prefix the flag usage gets.
+	Help string
+	// Validators are the registered validators named by its 'validate' tag.
+	Validators []string
+}
+
+// ParseType parses the tags of every arg field of structType (a struct or pointer to struct) into a TypeInfo. Every bad tag is reported together in the returned error, as AttachStructArgs reports them.
+func ParseType(structType reflect.Type) (*TypeInfo, error) {
+	return parseType(structType, Options{})
+}
+
+// ParseTypeWithOptions is ParseType reading the tags named by options.
+func ParseTypeWithOptions(structType reflect.Type, options Options) (*TypeInfo, error) {
+	return parseType(structType, options)
+}
+
+func parseType(structType reflect.Type, options Options) (*TypeInfo, error) {
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type [%v] is not a struct", structType)
+	}
+	fields, err := structArgs(structType, options)
+	if err != nil {
+		return nil, err
+	}
+	info := &TypeInfo{Type: structType, Fields: make([]FieldInfo, 0, len(fields)), StructValidator: reflect.PtrTo(structType).Implements(structValidatorType), options: options}
+	var errs []error
+	for _, field := range fields {
+		if field.err != nil {
+			errs = append(errs, inStruct(field.err, field.parent))
+			continue
+		}
+		info.Fields = append(info.Fields, FieldInfo{
+			Name:       field.Name,
+			Path:       field.path,
+			Index:      append([]int{}, field.Index...),
+			Struct:     field.parent,
+			Argument:   field.arg,
+			Help:       strings.TrimSpace(field.Tag.Get(options.helpTagKey())),
+			Validators: append([]string{}, field.arg.Validate...),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return info, nil
+}
+
+var structValidatorType = reflect.TypeOf((*StructValidator)(nil)).Elem()
+
+// Field returns the field whose flag has the long name longName, or that is the positional argument named so.
+func (info *TypeInfo) Field(longName string) (FieldInfo, bool) {
+	for _, field := range info.Fields {
+		if field.Argument.LongName == longName {
+			return field, true
+		}
+	}
+	return FieldInfo{}, false
+}
+
+// Attach is AttachStructArgs for target, a pointer to a value of info.Type, read with the options info was parsed with.
+func (info *TypeInfo) Attach(cmd *cobra.Command, target interface{}) error {
+	if reflect.TypeOf(target) != reflect.PtrTo(info.Type) {
+		return fmt.Errorf("target [%T] is not a pointer to %v", target, info.Type)
+	}
+	return attachStructArgs(cmd, target, info.options)
+}
+
+// Unmarshal is the Unmarshal of target, a pointer to a value of info.Type, read with the options info was parsed with.
+func (info *TypeInfo) Unmarshal(cmd *cobra.Command, target interface{}) error {
+	if reflect.TypeOf(target) != reflect.PtrTo(info.Type) {
+		return fmt.Errorf("target [%T] is not a pointer to %v", target, info.Type)
+	}
+	return unmarshal(cmd, target, info.options)
+}
@@ -0,0 +1,111 @@
+package cobraargs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseType(t *testing.T) {
+	type badArgs struct {
+		Port int    `arg:"defaultvalue=x"`
+		Name string `arg:"shortname=toolong"`
+	}
+	type documentedArgs struct {
+		Address string   `arg:"validate=hostname"`
+		Labels  []string `help:"labels"`
+	}
+	tests := []struct {
+		name           string
+		structType     reflect.Type
+		wantFields     []string
+		wantValidator  bool
+		wantValidators map[string][]string
+		wantHelp       map[string]string
+		wantPaths      map[string]string
+		wantErr        bool
+	}{
+		{name: "pointer", structType: reflect.TypeOf(&nestedArgs{}), wantFields: []string{"verbose", "primary-host", "primary-port", "replica-host", "replica-port", "since"}, wantPaths: map[string]string{"verbose": "Verbose", "replica-port": "Replica.Port"}},
+		{name: "struct validator", structType: reflect.TypeOf(spanArgs{}), wantValidator: true},
+		{name: "validators and help", structType: reflect.TypeOf(documentedArgs{}), wantValidators: map[string][]string{"address": {"hostname"}}, wantHelp: map[string]string{"labels": "labels"}},
+		{name: "not a struct", structType: reflect.TypeOf(0), wantErr: true},
+		{name: "bad tags", structType: reflect.TypeOf(badArgs{}), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := ParseType(test.structType)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseType error %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if info.StructValidator != test.wantValidator {
+				t.Errorf("StructValidator %v, want %v", info.StructValidator, test.wantValidator)
+			}
+			if test.wantFields != nil {
+				var names []string
+				for _, field := range info.Fields {
+					names = append(names, field.Argument.LongName)
+				}
+				if !reflect.DeepEqual(names, test.wantFields) {
+					t.Errorf("fields %q, want %q", names, test.wantFields)
+				}
+			}
+			for name, want := range test.wantValidators {
+				if field, _ := info.Field(name); !reflect.DeepEqual(field.Validators, want) {
+					t.Errorf("field %v validators %q, want %q", name, field.Validators, want)
+				}
+			}
+			for name, want := range test.wantHelp {
+				if field, _ := info.Field(name); field.Help != want {
+					t.Errorf("field %v help %q, want %q", name, field.Help, want)
+				}
+			}
+			for name, want := range test.wantPaths {
+				if field, _ := info.Field(name); field.Path != want {
+					t.Errorf("field %v path %q, want %q", name, field.Path, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeInfoAttach(t *testing.T) {
+	info, err := ParseType(reflect.TypeOf(dbConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		target  interface{}
+		wantErr bool
+	}{
+		{name: "matching type", target: &dbConfig{}},
+		{name: "other type", target: &nestedArgs{}, wantErr: true},
+		{name: "not a pointer", target: dbConfig{}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "db", Run: func(*cobra.Command, []string) {}}
+			if err := info.Attach(cmd, test.target); (err != nil) != test.wantErr {
+				t.Fatalf("Attach error %v, want error %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			cmd.SetArgs([]string{"--port", "1"})
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			var unmarshaled dbConfig
+			if err := info.Unmarshal(cmd, &unmarshaled); err != nil {
+				t.Fatal(err)
+			}
+			if unmarshaled != (dbConfig{Host: "localhost", Port: 1}) {
+				t.Errorf("Unmarshal got %+v", unmarshaled)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+)
+
+// ValidateStruct checks every arg field of structType (a struct or pointer to struct) for bad tag syntax, unknown tag keys, unparsable default values and duplicate long or short names, without attaching anything to a real command. Calling it from a test catches tag typos before the binary ships.
+func ValidateStruct(structType reflect.Type) error {
+	return validateStruct(structType, Options{})
+}
+
+func validateStruct(structType reflect.Type, options Options) error {
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("type [%v] is not a struct", structType)
+	}
+	var errs []error
+	fields, err := structArgs(structType, options)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		arg, err := field.arg, field.err
+		if err != nil {
+			continue // reported by AttachStructArgs below, including unknown keys in strict mode
+		}
+		for _, tagKey := range arg.UnknownTagKeys {
+			errs = append(errs, unknownTagKeyError(field.parent, field.Name, tagKey))
+		}
+	}
+	if StrictTagKeys() {
+		errs = nil
+	}
+	cmd := &cobra.Command{Use: structType.Name()}
+	if err := attachStructArgs(cmd, reflect.New(structType).Interface(), options); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+var strictTagKeys atomic.Bool
+
+// SetStrictTagKeys turns strict tag parsing on or off for the whole process. In strict mode ParseArgFromField, and so every Attach function, rejects unknown arg tag keys such as 'requird=true' instead of silently ignoring them.
+func SetStrictTagKeys(strict bool) {
+	strictTagKeys.Store(strict)
+	clearArgCache()
+}
+
+// StrictTagKeys reports whether strict tag parsing is on.
+func StrictTagKeys() bool {
+	return strictTagKeys.Load()
+}
+
+func unknownTagKeyError(parmType reflect.Type, fieldName, tagKey string) *ArgError {
+	message := fmt.Sprintf("field %v has unknown arg tag key [%v]", fieldName, tagKey)
+	if parmType != nil {
+		message = fmt.Sprintf("field %v.%v has unknown arg tag key [%v]", parmType.Name(), fieldName, tagKey)
+	}
+	if suggestion := suggestTagKey(tagKey); suggestion != "" {
+		message = fmt.Sprintf("%v, did you mean [%v]?", message, suggestion)
+	}
+	return newArgError(ErrUnknownTagKey, parmType, fieldName, tagKey, "", "%v", message)
+}
+
+// suggestTagKey returns the known tag key closest to tagKey, or "" when none is close.
+func suggestTagKey(tagKey string) string {
+	suggestion := ""
+	bestDistance := len(tagKey)/2 + 1
+	for _, known := range knownTagKeys {
+		if distance := editDistance(tagKey, known); distance < bestDistance {
+			suggestion = known
+			bestDistance = distance
+		}
+	}
+	return suggestion
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,48 @@
+package cobraargs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateStruct(t *testing.T) {
+	type validArgs struct {
+		Name  string `arg:"shortname=n,defaultvalue=bob"`
+		Port  int    `arg:"defaultvalue=80"`
+		Debug bool
+	}
+	type badDefault struct {
+		Port int `arg:"defaultvalue=http"`
+	}
+	type duplicateShort struct {
+		Name  string `arg:"shortname=n"`
+		Names string `arg:"shortname=n"`
+	}
+	type badSyntax struct {
+		Name string `arg:"defaultvalue='x"`
+	}
+	type longShort struct {
+		Name string `arg:"shortname=nm"`
+	}
+	tests := []struct {
+		name       string
+		structType reflect.Type
+		wantErr    error
+	}{
+		{name: "valid", structType: reflect.TypeOf(validArgs{})},
+		{name: "pointer", structType: reflect.TypeOf(&validArgs{})},
+		{name: "bad default", structType: reflect.TypeOf(badDefault{}), wantErr: ErrBadDefaultValue},
+		{name: "duplicate short name", structType: reflect.TypeOf(duplicateShort{}), wantErr: ErrDuplicateName},
+		{name: "bad syntax", structType: reflect.TypeOf(badSyntax{}), wantErr: ErrInvalidTagSyntax},
+		{name: "short name too long", structType: reflect.TypeOf(longShort{}), wantErr: ErrShortNameTooLong},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateStruct(test.structType)
+			if (err == nil) != (test.wantErr == nil) || !errors.Is(err, test.wantErr) {
+				t.Errorf("ValidateStruct error %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// validateHookAnnotation marks a command whose pre run hooks already call ValidateFlags.
+const validateHookAnnotation = "cobraargs_validate_hook"
+
+// flagCheck is a validation tag: the flag annotation it is recorded under and the check run against the flag after parsing.
+type flagCheck struct {
+	annotation string
+	check      func(cmd *cobra.Command, flag *pflag.Flag, values []string) error
+}
+
+// flagChecks lists the validation tags in the order ValidateFlags runs them.
+var flagChecks = []flagCheck{
+	{annotation: "cobraargs_requiredif", check: checkRequiredIf},
+	{annotation: "cobraargs_requiredunless", check: checkRequiredUnless},
+	{annotation: "cobraargs_range", check: checkRange},
+	{annotation: "cobraargs_length", check: checkLength},
+	{annotation: "cobraargs_pattern", check: checkPattern},
+	{annotation: "cobraargs_exists", check: checkExists},
+	{annotation: "cobraargs_validate", check: checkValidators},
+	{annotation: "cobraargs_requires", check: checkRequires},
+	{annotation: "cobraargs_conflicts", check: checkConflicts},
+}
+
+// stringFlagTypes lists the pflag value types 'minlen', 'maxlen', 'pattern' and 'exists' apply to.
+var stringFlagTypes = []string{"string", "stringSlice", "stringArray"}
+
+// numericFlagTypes lists the pflag value types 'min' and 'max' apply to.
+var numericFlagTypes = []string{
+	"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "count",
+	"intSlice", "int32Slice", "int64Slice", "uintSlice", "float32Slice", "float64Slice",
+}
+
+// ValidateFlags runs the validation tags, such as 'requiredif', of every attached flag of cmd and reports every failure together. Attaching a flag with a validation tag installs a hook calling it, after the env, config file and profile steps, before the command runs.
+func ValidateFlags(cmd *cobra.Command) error {
+	var errs []error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		for _, check := range flagChecks {
+			if values, has := flag.Annotations[check.annotation]; has {
+				if err := check.check(cmd, flag, values); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// bindValidationArgs records the validation tags of arg on its flag.
+func bindValidationArgs(cmd *cobra.Command, arg Argument) error {
+	if arg.RequiredIf != "" {
+		if err := bindValidationArg(cmd, arg, "cobraargs_requiredif", []string{arg.RequiredIf}); err != nil {
+			return err
+		}
+	}
+	if arg.RequiredUnless != "" {
+		if err := bindValidationArg(cmd, arg, "cobraargs_requiredunless", []string{arg.RequiredUnless}); err != nil {
+			return err
+		}
+	}
+	if arg.Min != "" || arg.Max != "" {
+		if valueType := argFlags(cmd, arg).Lookup(arg.LongName).Value.Type(); !containsString(numericFlagTypes, valueType) {
+			return fmt.Errorf("flag --%v has 'min' or 'max' but its type %v is not numeric", arg.LongName, valueType)
+		}
+		if err := bindValidationArg(cmd, arg, "cobraargs_range", []string{arg.Min, arg.Max}); err != nil {
+			return err
+		}
+	}
+	if arg.MinLen != 0 || arg.MaxLen != 0 || arg.Pattern != "" {
+		if valueType := argFlags(cmd, arg).Lookup(arg.LongName).Value.Type(); !containsString(stringFlagTypes, valueType) {
+			return fmt.Errorf("flag --%v has 'minlen', 'maxlen' or 'pattern' but its type %v is not a string", arg.LongName, valueType)
+		}
+	}
+	if arg.MinLen != 0 || arg.MaxLen != 0 {
+		if err := bindValidationArg(cmd, arg, "cobraargs_length", []string{strconv.Itoa(arg.MinLen), strconv.Itoa(arg.MaxLen)}); err != nil {
+			return err
+		}
+	}
+	if arg.Pattern != "" {
+		if err := bindValidationArg(cmd, arg, "cobraargs_pattern", []string{arg.Pattern}); err != nil {
+			return err
+		}
+	}
+	if len(arg.Requires) > 0 {
+		if err := bindValidationArg(cmd, arg, "cobraargs_requires", arg.Requires); err != nil {
+			return err
+		}
+	}
+	if len(arg.Conflicts) > 0 {
+		if err := bindValidationArg(cmd, arg, "cobraargs_conflicts", arg.Conflicts); err != nil {
+			return err
+		}
+	}
+	if len(arg.Validate) > 0 {
+		if err := bindValidationArg(cmd, arg, "cobraargs_validate", arg.Validate); err != nil {
+			return err
+		}
+	}
+	if arg.Exists != "" {
+		return bindExistsArg(cmd, arg)
+	}
+	return nil
+}
+
+// bindValidationArg records a validation tag of arg on its flag and makes sure cmd validates its flags before running.
+func bindValidationArg(cmd *cobra.Command, arg Argument, annotation string, values []string) error {
+	if err := argFlags(cmd, arg).SetAnnotation(arg.LongName, annotation, values); err != nil {
+		return err
+	}
+	if arg.Persistent {
+		if cmd.Annotations[validateHookAnnotation+"_persistent"] == "" {
+			if cmd.Annotations == nil {
+				cmd.Annotations = map[string]string{}
+			}
+			cmd.Annotations[validateHookAnnotation+"_persistent"] = "true"
+			installPostPreRunHook(cmd, stageValidate, validateResolvedFlags)
+		}
+		return nil
+	}
+	installPreRunHook(cmd, validateHookAnnotation, false, validateResolvedFlags)
+	return nil
+}
+
+// validateResolvedFlags applies the env step, which the PreRunE hook doing it may not have run yet, then validates.
+func validateResolvedFlags(cmd *cobra.Command) error {
+	if err := ApplyEnvArgs(cmd); err != nil {
+		return err
+	}
+	return ValidateFlags(cmd)
+}
+
+// flagCondition splits a 'requiredif' style condition flag=value|value into the other flag and the values that trigger it.
+func flagCondition(cmd *cobra.Command, flag *pflag.Flag, tagKey, condition string) (*pflag.Flag, []string, error) {
+	name, values, _ := strings.Cut(condition, "=")
+	other := cmd.Flag(name)
+	if other == nil {
+		return nil, nil, fmt.Errorf("flag --%v has '%v' on --%v which is not a flag of command %v", flag.Name, tagKey, name, cmd.Name())
+	}
+	return other, strings.Split(values, "|"), nil
+}
+
+func checkRequiredIf(cmd *cobra.Command, flag *pflag.Flag, conditions []string) error {
+	if flag.Changed {
+		return nil
+	}
+	for _, condition := range conditions {
+		other, values, err := flagCondition(cmd, flag, "requiredif", condition)
+		if err != nil {
+			return err
+		}
+		if containsString(values, other.Value.String()) {
+			return fmt.Errorf("flag --%v is required when --%v is %v", flag.Name, other.Name, quotedFlagValue(other, other.Value.String()))
+		}
+	}
+	return nil
+}
+
+func checkRequiredUnless(cmd *cobra.Command, flag *pflag.Flag, conditions []string) error {
+	if flag.Changed {
+		return nil
+	}
+	for _, condition := range conditions {
+		other, values, err := flagCondition(cmd, flag, "requiredunless", condition)
+		if err != nil {
+			return err
+		}
+		if !containsString(values, other.Value.String()) {
+			return fmt.Errorf("flag --%v is required unless --%v is %v", flag.Name, other.Name, strings.Join(values, " or "))
+		}
+	}
+	return nil
+}
+
+// checkRange checks every number a flag was set to against its 'min' and 'max' bounds, compared as exact rationals so large integers keep their precision.
+func checkRange(_ *cobra.Command, flag *pflag.Flag, bounds []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	min, max := bounds[0], bounds[1]
+	for _, value := range flagValues(flag) {
+		number, ok := new(big.Rat).SetString(value)
+		if !ok {
+			continue
+		}
+		below := min != "" && number.Cmp(ratOf(min)) < 0
+		above := max != "" && number.Cmp(ratOf(max)) > 0
+		switch {
+		case (below || above) && min != "" && max != "":
+			return fmt.Errorf("flag --%v is %v, it must be between %v and %v", flag.Name, quotedFlagValue(flag, value), min, max)
+		case below:
+			return fmt.Errorf("flag --%v is %v, it must be at least %v", flag.Name, quotedFlagValue(flag, value), min)
+		case above:
+			return fmt.Errorf("flag --%v is %v, it must be at most %v", flag.Name, quotedFlagValue(flag, value), max)
+		}
+	}
+	return nil
+}
+
+func ratOf(number string) *big.Rat {
+	rat, _ := new(big.Rat).SetString(number)
+	return rat
+}
+
+func checkLength(_ *cobra.Command, flag *pflag.Flag, bounds []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	minLen, _ := strconv.Atoi(bounds[0])
+	maxLen, _ := strconv.Atoi(bounds[1])
+	for _, value := range flagValues(flag) {
+		length := utf8.RuneCountInString(value)
+		switch {
+		case minLen != 0 && length < minLen:
+			return fmt.Errorf("flag --%v value %v is %v characters long, it must be at least %v", flag.Name, quotedFlagValue(flag, value), length, minLen)
+		case maxLen != 0 && length > maxLen:
+			return fmt.Errorf("flag --%v value %v is %v characters long, it must be at most %v", flag.Name, quotedFlagValue(flag, value), length, maxLen)
+		}
+	}
+	return nil
+}
+
+func checkPattern(_ *cobra.Command, flag *pflag.Flag, patterns []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	pattern := regexp.MustCompile(patterns[0])
+	for _, value := range flagValues(flag) {
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("flag --%v value %v does not match the pattern %v", flag.Name, quotedFlagValue(flag, value), patterns[0])
+		}
+	}
+	return nil
+}
+
+// flagValues returns each element of a slice flag, or the single value of any other flag.
+func flagValues(flag *pflag.Flag) []string {
+	if sliceValue, ok := unwrapValue(flag.Value).(pflag.SliceValue); ok {
+		return sliceValue.GetSlice()
+	}
+	return []string{flag.Value.String()}
+}
+
+// quotedFlagValue quotes value for an error message, unless flag is a 'secret' one.
+func quotedFlagValue(flag *pflag.Flag, value string) string {
+	if isSecretFlag(flag) {
+		return RedactedValue
+	}
+	return strconv.Quote(value)
+}
+
+func checkRequires(cmd *cobra.Command, flag *pflag.Flag, names []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	var errs []error
+	for _, name := range names {
+		other := cmd.Flag(name)
+		switch {
+		case other == nil:
+			errs = append(errs, fmt.Errorf("flag --%v has 'requires' on --%v which is not a flag of command %v", flag.Name, name, cmd.Name()))
+		case !other.Changed:
+			errs = append(errs, fmt.Errorf("flag --%v requires flag --%v to be set", flag.Name, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkConflicts(cmd *cobra.Command, flag *pflag.Flag, names []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	var errs []error
+	for _, name := range names {
+		other := cmd.Flag(name)
+		switch {
+		case other == nil:
+			errs = append(errs, fmt.Errorf("flag --%v has 'conflicts' on --%v which is not a flag of command %v", flag.Name, name, cmd.Name()))
+		case other.Changed:
+			errs = append(errs, fmt.Errorf("flag --%v can't be set together with flag --%v", flag.Name, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bindExistsArg records the 'exists' tag of arg on its flag and has shells complete it as a file name or directory name.
+func bindExistsArg(cmd *cobra.Command, arg Argument) error {
+	flags := argFlags(cmd, arg)
+	if valueType := flags.Lookup(arg.LongName).Value.Type(); !containsString(stringFlagTypes, valueType) {
+		return fmt.Errorf("flag --%v has 'exists' but its type %v is not a string", arg.LongName, valueType)
+	}
+	if err := bindValidationArg(cmd, arg, "cobraargs_exists", []string{arg.Exists}); err != nil {
+		return err
+	}
+	if arg.Exists == "dir" {
+		return cobra.MarkFlagDirname(flags, arg.LongName)
+	}
+	return cobra.MarkFlagFilename(flags, arg.LongName)
+}
+
+func checkExists(_ *cobra.Command, flag *pflag.Flag, kinds []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	for _, path := range flagValues(flag) {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			return fmt.Errorf("flag --%v %v %v", flag.Name, existsNoun(kinds[0]), statErrorMessage(path, err))
+		case kinds[0] == "dir" && !info.IsDir():
+			return fmt.Errorf("flag --%v directory %q is not a directory", flag.Name, path)
+		case kinds[0] == "file" && info.IsDir():
+			return fmt.Errorf("flag --%v file %q is a directory", flag.Name, path)
+		}
+	}
+	return nil
+}
+
+func existsNoun(kind string) string {
+	if kind == "dir" {
+		return "directory"
+	}
+	return "file"
+}
+
+func statErrorMessage(path string, err error) string {
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Sprintf("%q does not exist", path)
+	}
+	return fmt.Sprintf("%q can't be read: %v", path, err)
+}
+
+// checkValidators runs the validators named by a 'validate' tag on every value of flag, looking them up when run so a validator registered again replaces the earlier one.
+func checkValidators(_ *cobra.Command, flag *pflag.Flag, names []string) error {
+	if !flag.Changed {
+		return nil
+	}
+	for _, name := range names {
+		validator, has := lookupValidator(name)
+		if !has {
+			return fmt.Errorf("flag --%v has validator %v which is not registered", flag.Name, name)
+		}
+		for _, value := range flagValues(flag) {
+			if err := validator(flag.Name, value); err != nil {
+				if isSecretFlag(flag) {
+					return fmt.Errorf("flag --%v value %v is not valid for validator %v", flag.Name, RedactedValue, name)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StructValidator is implemented by arg structs with checks across fields, such as --start being before --end. AttachStructArgs makes cmd call Validate once every field is set, after the validation tags pass, before the command runs.
+type StructValidator interface {
+	Validate() error
+}
+
+// installStructValidator calls validator before cmd runs, or before cmd or any of its subcommands runs when the struct has persistent fields.
+func installStructValidator(cmd *cobra.Command, validator StructValidator, persistent bool) {
+	apply := func(cmd *cobra.Command) error {
+		if err := validateResolvedFlags(cmd); err != nil {
+			return err
+		}
+		return validator.Validate()
+	}
+	if persistent {
+		installPostPreRunHook(cmd, stageValidate, apply)
+		return
+	}
+	cmd.PreRunE = withPreRunHook(apply, cmd.PreRunE, cmd.PreRun)
+	cmd.PreRun = nil
+}
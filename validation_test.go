@@ -0,0 +1,234 @@
+package cobraargs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// executeValidated attaches target to a new command and reports the error of executing it with args.
+func executeValidated(t *testing.T, target interface{}, args []string) error {
+	t.Helper()
+	cmd := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	if err := AttachStructArgs(cmd, target); err != nil {
+		t.Fatal(err)
+	}
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func TestRequiredIf(t *testing.T) {
+	type tlsArgs struct {
+		Mode   string `arg:"defaultvalue=plain"`
+		Cert   string `arg:"requiredif=mode=tls|mtls"`
+		Remote bool
+		Cache  string `arg:"requiredunless=remote=true"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "condition unmet", args: []string{"--cache", "c"}},
+		{name: "requiredif met", args: []string{"--mode", "tls", "--cert", "c.pem", "--cache", "c"}},
+		{name: "requiredif missing", args: []string{"--mode", "tls", "--cache", "c"}, wantErr: true},
+		{name: "second value", args: []string{"--mode", "mtls", "--cache", "c"}, wantErr: true},
+		{name: "requiredunless excused", args: []string{"--remote"}},
+		{name: "requiredunless missing", args: []string{}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &tlsArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRangeValidation(t *testing.T) {
+	type rangeArgs struct {
+		Workers int     `arg:"min=1,max=64,defaultvalue=4"`
+		Ratio   float64 `arg:"min=0,max=0.5"`
+		Ports   []int   `arg:"min=1,max=65535"`
+		Big     uint64  `arg:"max=18446744073709551615"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "lower bound", args: []string{"--workers", "1"}},
+		{name: "upper bound", args: []string{"--workers", "64"}},
+		{name: "below min", args: []string{"--workers", "0"}, wantErr: true},
+		{name: "above max", args: []string{"--workers", "65"}, wantErr: true},
+		{name: "fraction", args: []string{"--ratio", "0.25"}},
+		{name: "fraction above max", args: []string{"--ratio", "0.51"}, wantErr: true},
+		{name: "slice in range", args: []string{"--ports", "80,443"}},
+		{name: "slice out of range", args: []string{"--ports", "80,70000"}, wantErr: true},
+		{name: "max uint64", args: []string{"--big", "18446744073709551615"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &rangeArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestLengthAndPatternValidation(t *testing.T) {
+	type nameArgs struct {
+		Name  string   `arg:"minlen=2,maxlen=8,defaultvalue=app"`
+		Slug  string   `arg:"pattern=^[a-z]+(-[a-z]+)*$"`
+		Tags  []string `arg:"maxlen=3"`
+		Emoji string   `arg:"maxlen=2"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "too short", args: []string{"--name", "a"}, wantErr: true},
+		{name: "too long", args: []string{"--name", "abcdefghi"}, wantErr: true},
+		{name: "pattern match", args: []string{"--slug", "my-app"}},
+		{name: "pattern mismatch", args: []string{"--slug", "My-App"}, wantErr: true},
+		{name: "pattern anchored", args: []string{"--slug", "app!"}, wantErr: true},
+		{name: "each slice item", args: []string{"--tags", "a", "--tags", "abcd"}, wantErr: true},
+		{name: "counts runes", args: []string{"--emoji", "éé"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &nameArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestExistsValidation(t *testing.T) {
+	type pathArgs struct {
+		Config string   `arg:"exists=file"`
+		Data   string   `arg:"exists=dir"`
+		Extra  []string `arg:"exists=file"`
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "file", args: []string{"--config", file}},
+		{name: "missing file", args: []string{"--config", missing}, wantErr: true},
+		{name: "file is a directory", args: []string{"--config", dir}, wantErr: true},
+		{name: "dir", args: []string{"--data", dir}},
+		{name: "dir is a file", args: []string{"--data", file}, wantErr: true},
+		{name: "each slice item", args: []string{"--extra", file, "--extra", missing}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &pathArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+type spanArgs struct {
+	Start int
+	End   int `arg:"min=0"`
+}
+
+func (args *spanArgs) Validate() error {
+	if args.End < args.Start {
+		return errors.New("--end is before --start")
+	}
+	return nil
+}
+
+func TestStructValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "valid", args: []string{"--start", "1", "--end", "2"}},
+		{name: "invalid", args: []string{"--start", "2", "--end", "1"}, wantErr: "--end is before --start"},
+		{name: "tags checked first", args: []string{"--start", "2", "--end", "-1"}, wantErr: "must be at least 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := executeValidated(t, &spanArgs{}, test.args)
+			if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("Execute error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequiresAndConflicts(t *testing.T) {
+	type loginArgs struct {
+		User     string `arg:"requires=password"`
+		Password string
+		Token    string `arg:"conflicts=user|password"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "requires met", args: []string{"--user", "u", "--password", "p"}},
+		{name: "requires missing", args: []string{"--user", "u"}, wantErr: true},
+		{name: "one way", args: []string{"--password", "p"}},
+		{name: "no conflict", args: []string{"--token", "t"}},
+		{name: "conflicts", args: []string{"--token", "t", "--user", "u", "--password", "p"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &loginArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidationRedactsSecrets(t *testing.T) {
+	type secretArgs struct {
+		Pin   int    `arg:"secret,min=1000,max=9999"`
+		Token string `arg:"secret"`
+		Cert  string `arg:"requiredif=token=hunter2"`
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		secret string
+	}{
+		{name: "range", args: []string{"--pin", "31415926"}, secret: "31415926"},
+		{name: "requiredif source", args: []string{"--token", "hunter2"}, secret: "hunter2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := executeValidated(t, &secretArgs{}, test.args)
+			if err == nil {
+				t.Fatal("Execute succeeded, want an error")
+			}
+			if strings.Contains(err.Error(), test.secret) || !strings.Contains(err.Error(), RedactedValue) {
+				t.Errorf("Execute error %q shows the secret value or does not redact it", err)
+			}
+		})
+	}
+}
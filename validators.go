@@ -0,0 +1,87 @@
+package cobraargs
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	validatorsLock sync.RWMutex
+	validators     = map[string]func(flagName, value string) error{
+		"port":     validatePort,
+		"hostname": validateHostname,
+		"email":    validateEmail,
+		"uuid":     validateUUID,
+		"semver":   validateSemver,
+	}
+)
+
+// RegisterValidator makes fields tagged 'validate=<name>' check every value their flag is set to with fn before the command runs. Register before attaching, as the 'validate' tag only accepts known names. The port, hostname, email, uuid and semver validators are built in and may be replaced.
+func RegisterValidator(name string, fn func(flagName, value string) error) {
+	validatorsLock.Lock()
+	defer validatorsLock.Unlock()
+	validators[strings.ToLower(name)] = fn
+	clearArgCache()
+}
+
+func lookupValidator(name string) (func(flagName, value string) error, bool) {
+	validatorsLock.RLock()
+	defer validatorsLock.RUnlock()
+	fn, has := validators[name]
+	return fn, has
+}
+
+func validatePort(flagName, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("flag --%v value %q is not a port between 1 and 65535", flagName, value)
+	}
+	return nil
+}
+
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// validateHostname accepts RFC 1123 host names, with an optional trailing dot.
+func validateHostname(flagName, value string) error {
+	name := strings.TrimSuffix(value, ".")
+	valid := name != "" && len(name) <= 253
+	for _, label := range strings.Split(name, ".") {
+		valid = valid && hostnameLabel.MatchString(label)
+	}
+	if !valid {
+		return fmt.Errorf("flag --%v value %q is not a valid hostname", flagName, value)
+	}
+	return nil
+}
+
+// validateEmail accepts a bare address such as jane@example.com, not a display name form such as "Jane <jane@example.com>".
+func validateEmail(flagName, value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return fmt.Errorf("flag --%v value %q is not a valid email address", flagName, value)
+	}
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validateUUID(flagName, value string) error {
+	if !uuidPattern.MatchString(value) {
+		return fmt.Errorf("flag --%v value %q is not a valid UUID", flagName, value)
+	}
+	return nil
+}
+
+// semverPattern is the pattern suggested by semver.org, with an optional leading 'v' as Go module versions have.
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func validateSemver(flagName, value string) error {
+	if !semverPattern.MatchString(value) {
+		return fmt.Errorf("flag --%v value %q is not a valid semantic version", flagName, value)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cobraargs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseArgValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		want        []string
+		wantUnknown []string
+		wantErr     error
+	}{
+		{name: "one", tag: `validate=port`, want: []string{"port"}},
+		{name: "pipe separated", tag: `validate=port|hostname`, want: []string{"port", "hostname"}},
+		{name: "quoted comma separated", tag: `validate='port,hostname'`, want: []string{"port", "hostname"}},
+		{name: "comma separated", tag: `validate=port,hostname`, want: []string{"port", "hostname"}},
+		{name: "comma separated then keys", tag: `validate=port,hostname,required,shortname=p`, want: []string{"port", "hostname"}},
+		{name: "key between", tag: `validate=port,required,hostname`, want: []string{"port"}, wantUnknown: []string{"hostname"}},
+		{name: "unknown bare key", tag: `validate=port,nosuch`, want: []string{"port"}, wantUnknown: []string{"nosuch"}},
+		{name: "unregistered", tag: `validate=nosuch`, wantErr: ErrInvalidTagValue},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			field := reflect.StructField{Name: "Addr", Type: reflect.TypeOf(""), Tag: reflect.StructTag(`arg:"` + test.tag + `"`)}
+			arg, err := parseArgTag(field, test.tag, DefaultNameStyle)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("parseArgTag(%q) error %v, want %v", test.tag, err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(arg.Validate, test.want) {
+				t.Errorf("parseArgTag(%q) validators %q, want %q", test.tag, arg.Validate, test.want)
+			}
+			if !reflect.DeepEqual(arg.UnknownTagKeys, test.wantUnknown) {
+				t.Errorf("parseArgTag(%q) unknown keys %q, want %q", test.tag, arg.UnknownTagKeys, test.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestValidators(t *testing.T) {
+	RegisterValidator("even", func(flagName, value string) error {
+		if n, err := strconv.Atoi(value); err != nil || n%2 != 0 {
+			return fmt.Errorf("flag --%v value %q is not even", flagName, value)
+		}
+		return nil
+	})
+	type validatedArgs struct {
+		Address string `arg:"validate=hostname"`
+		Port    string `arg:"validate=port"`
+		Count   int    `arg:"validate=even"`
+		Mail    string `arg:"validate=email"`
+		Token   string `arg:"validate=uuid"`
+		Version string `arg:"validate=semver"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "hostname", args: []string{"--address", "example.com."}},
+		{name: "bad hostname", args: []string{"--address", "not a host"}, wantErr: true},
+		{name: "port", args: []string{"--port", "8080"}},
+		{name: "bad port", args: []string{"--port", "65536"}, wantErr: true},
+		{name: "registered", args: []string{"--count", "4"}},
+		{name: "registered fails", args: []string{"--count", "3"}, wantErr: true},
+		{name: "email", args: []string{"--mail", "jane@example.com"}},
+		{name: "email display name", args: []string{"--mail", "Jane <jane@example.com>"}, wantErr: true},
+		{name: "uuid", args: []string{"--token", "123e4567-e89b-12d3-a456-426614174000"}},
+		{name: "bad uuid", args: []string{"--token", "123e4567"}, wantErr: true},
+		{name: "semver", args: []string{"--version", "v1.2.3-rc.1+build"}},
+		{name: "bad semver", args: []string{"--version", "1.2"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := executeValidated(t, &validatedArgs{}, test.args); (err != nil) != test.wantErr {
+				t.Errorf("Execute error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
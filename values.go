@@ -0,0 +1,369 @@
+package cobraargs
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// timeValue is a pflag.Value for time.Time parsed with a layout.
+type timeValue struct {
+	value  *time.Time
+	layout string
+}
+
+func newTimeValue(value *time.Time, layout string) *timeValue {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return &timeValue{value: value, layout: layout}
+}
+
+func (t *timeValue) Set(val string) error {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch val {
+	case "now":
+		*t.value = now
+	case "today":
+		*t.value = today
+	case "yesterday":
+		*t.value = today.AddDate(0, 0, -1)
+	case "tomorrow":
+		*t.value = today.AddDate(0, 0, 1)
+	default:
+		parsed, err := time.Parse(t.layout, val)
+		if err != nil {
+			return fmt.Errorf("value [%v] does not match the time layout %v", val, t.layout)
+		}
+		*t.value = parsed
+	}
+	return nil
+}
+
+func (t *timeValue) String() string {
+	if t.value == nil || t.value.IsZero() {
+		return ""
+	}
+	return t.value.Format(t.layout)
+}
+
+func (t *timeValue) Type() string {
+	return "time"
+}
+
+// urlValue is a pflag.Value for *url.URL restricted to a set of schemes.
+type urlValue struct {
+	value   **url.URL
+	schemes []string
+}
+
+func newURLValue(value **url.URL, schemes []string) *urlValue {
+	return &urlValue{value: value, schemes: schemes}
+}
+
+func (u *urlValue) Set(val string) error {
+	parsed, err := url.Parse(val)
+	if err != nil {
+		return err
+	}
+	if len(u.schemes) > 0 && !containsString(u.schemes, strings.ToLower(parsed.Scheme)) {
+		return fmt.Errorf("url [%v] has scheme [%v], expected one of %v", val, parsed.Scheme, strings.Join(u.schemes, "|"))
+	}
+	*u.value = parsed
+	return nil
+}
+
+func (u *urlValue) String() string {
+	if u.value == nil || *u.value == nil {
+		return ""
+	}
+	return (*u.value).String()
+}
+
+func (u *urlValue) Type() string {
+	return "url"
+}
+
+func containsString(items []string, item string) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+	return false
+}
+
+// byteSizeMultipliers maps size suffixes to their multiplier. Single letters and 'iB' suffixes are powers of 1024, 'B' suffixes are powers of 1000.
+var byteSizeMultipliers = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kib": 1 << 10,
+	"kb":  1e3,
+	"m":   1 << 20,
+	"mib": 1 << 20,
+	"mb":  1e6,
+	"g":   1 << 30,
+	"gib": 1 << 30,
+	"gb":  1e9,
+	"t":   1 << 40,
+	"tib": 1 << 40,
+	"tb":  1e12,
+	"p":   1 << 50,
+	"pib": 1 << 50,
+	"pb":  1e15,
+}
+
+// byteSizeValue is a pflag.Value for an int64 number of bytes given as a human-friendly size.
+type byteSizeValue struct {
+	value *int64
+	text  string
+}
+
+func newByteSizeValue(value *int64) *byteSizeValue {
+	return &byteSizeValue{value: value}
+}
+
+func (b *byteSizeValue) Set(val string) error {
+	size, err := parseByteSize(val)
+	if err != nil {
+		return err
+	}
+	*b.value = size
+	b.text = strings.TrimSpace(val)
+	return nil
+}
+
+func (b *byteSizeValue) String() string {
+	if b.text != "" {
+		return b.text
+	}
+	if b.value == nil {
+		return "0"
+	}
+	return strconv.FormatInt(*b.value, 10)
+}
+
+func (b *byteSizeValue) Type() string {
+	return "bytesize"
+}
+
+func parseByteSize(val string) (int64, error) {
+	trimmed := strings.TrimSpace(val)
+	index := len(trimmed)
+	for index > 0 && !isByteSizeNumber(trimmed[index-1]) {
+		index--
+	}
+	number, err := strconv.ParseFloat(trimmed[:index], 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("value [%v] is not a byte size", val)
+	}
+	multiplier, has := byteSizeMultipliers[strings.ToLower(strings.TrimSpace(trimmed[index:]))]
+	if !has {
+		return 0, fmt.Errorf("value [%v] has an unknown byte size suffix", val)
+	}
+	size := number * multiplier
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("value [%v] is too large for a byte size", val)
+	}
+	return int64(size), nil
+}
+
+func isByteSizeNumber(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.'
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// fieldPointerValue is implemented by the pflag.Value adapters that hold a pointer to the bound field.
+type fieldPointerValue interface {
+	fieldPointer() reflect.Value
+}
+
+// textValue is a pflag.Value adapter for types implementing encoding.TextUnmarshaler. The value is a pointer to the underlying field value.
+type textValue struct {
+	value reflect.Value
+}
+
+// textValueOf wraps the field when either its address or the field itself (a pointer) implements encoding.TextUnmarshaler. A nil pointer field is allocated.
+func textValueOf(variableValue interface{}) (*textValue, bool) {
+	pointer := reflect.ValueOf(variableValue)
+	if pointer.Type().Implements(textUnmarshalerType) {
+		return &textValue{value: pointer}, true
+	}
+	fieldValue := pointer.Elem()
+	if fieldValue.Kind() != reflect.Ptr || !fieldValue.Type().Implements(textUnmarshalerType) {
+		return nil, false
+	}
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+	}
+	return &textValue{value: fieldValue}, true
+}
+
+func (t *textValue) Set(val string) error {
+	return t.value.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+}
+
+func (t *textValue) String() string {
+	var marshaler encoding.TextMarshaler
+	if t.value.Type().Implements(textMarshalerType) {
+		marshaler = t.value.Interface().(encoding.TextMarshaler)
+	} else if t.value.Elem().Type().Implements(textMarshalerType) {
+		marshaler = t.value.Elem().Interface().(encoding.TextMarshaler)
+	} else {
+		return fmt.Sprint(t.value.Elem().Interface())
+	}
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+func (t *textValue) fieldPointer() reflect.Value {
+	return t.value
+}
+
+func (t *textValue) Type() string {
+	return strings.ToLower(t.value.Type().Elem().Name())
+}
+
+// oneOfValue wraps a pflag.Value, rejecting values that are not one of the choices.
+type oneOfValue struct {
+	pflag.Value
+	choices []string
+	// descriptions are the 'choicehelp' of the choices, if any, for the completion specs
+	descriptions []string
+}
+
+func (o *oneOfValue) unwrap() pflag.Value {
+	return o.Value
+}
+
+func (o *oneOfValue) Set(val string) error {
+	if !containsString(o.choices, val) {
+		return fmt.Errorf("value [%v] is not one of %v", val, strings.Join(o.choices, "|"))
+	}
+	return o.Value.Set(val)
+}
+
+// jsonValue is a pflag.Value that unmarshals JSON text into the field pointed to by value.
+type jsonValue struct {
+	value reflect.Value
+}
+
+// jsonValueOf wraps json.RawMessage fields and fields tagged 'format=json'.
+func jsonValueOf(arg Argument, variableValue interface{}) (*jsonValue, bool) {
+	_, isRaw := variableValue.(*json.RawMessage)
+	if !isRaw && arg.Format != "json" {
+		return nil, false
+	}
+	return &jsonValue{value: reflect.ValueOf(variableValue)}, true
+}
+
+// Set unmarshals val into the field. An empty val resets the field to its zero value.
+func (j *jsonValue) Set(val string) error {
+	parsed := reflect.New(j.value.Type().Elem())
+	if val == "" {
+		j.value.Elem().Set(parsed.Elem())
+		return nil
+	}
+	if err := json.Unmarshal([]byte(val), parsed.Interface()); err != nil {
+		return fmt.Errorf("value [%v] is not valid JSON: %v", val, err)
+	}
+	j.value.Elem().Set(parsed.Elem())
+	return nil
+}
+
+func (j *jsonValue) String() string {
+	if j.value.Elem().IsZero() {
+		return ""
+	}
+	if raw, ok := j.value.Interface().(*json.RawMessage); ok {
+		return string(*raw)
+	}
+	text, err := json.Marshal(j.value.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+func (j *jsonValue) fieldPointer() reflect.Value {
+	return j.value
+}
+
+func (j *jsonValue) Type() string {
+	return "json"
+}
+
+// fileModeValue is a pflag.Value for os.FileMode parsed and printed in octal.
+type fileModeValue struct {
+	value *os.FileMode
+}
+
+func newFileModeValue(value *os.FileMode) *fileModeValue {
+	return &fileModeValue{value: value}
+}
+
+func (f *fileModeValue) Set(val string) error {
+	mode, err := strconv.ParseUint(strings.TrimSpace(val), 8, 32)
+	if err != nil {
+		return fmt.Errorf("value [%v] is not an octal file mode", val)
+	}
+	*f.value = os.FileMode(mode)
+	return nil
+}
+
+func (f *fileModeValue) String() string {
+	if f.value == nil {
+		return "0"
+	}
+	return fmt.Sprintf("%#o", uint32(*f.value))
+}
+
+func (f *fileModeValue) Type() string {
+	return "filemode"
+}
+
+// negatedBoolValue is the value of a --no-<name> flag: setting it true sets the wrapped bool flag false, and marks that flag Changed so the config and env loaders leave it alone.
+type negatedBoolValue struct {
+	pflag.Value
+	flag *pflag.Flag
+}
+
+func (value *negatedBoolValue) Set(raw string) error {
+	negated, err := strconv.ParseBool(raw)
+	if err != nil {
+		return err
+	}
+	if err = value.Value.Set(strconv.FormatBool(!negated)); err != nil {
+		return err
+	}
+	if value.flag != nil {
+		value.flag.Changed = true
+	}
+	return nil
+}
+
+func (value *negatedBoolValue) String() string {
+	return "false"
+}
+
+func (value *negatedBoolValue) IsBoolFlag() bool {
+	return true
+}
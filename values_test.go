@@ -0,0 +1,539 @@
+package cobraargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "512", want: 512},
+		{value: "512B", want: 512},
+		{value: "1k", want: 1024},
+		{value: "1KiB", want: 1024},
+		{value: "1kb", want: 1000},
+		{value: "1 MB", want: 1000000},
+		{value: "2GiB", want: 2 << 30},
+		{value: " 1.5K ", want: 1536},
+		{value: "0.5mib", want: 512 << 10},
+		{value: "8191PiB", want: 8191 << 50},
+		{value: "9007199254740992", want: 1 << 53},
+		{value: "-1K", wantErr: true},
+		{value: "K", wantErr: true},
+		{value: "1.2.3", wantErr: true},
+		{value: "1XB", wantErr: true},
+		{value: "1 kilobyte", wantErr: true},
+		{value: "8192PiB", wantErr: true},
+		{value: "100000PB", wantErr: true},
+		{value: "9223372036854775807", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := parseByteSize(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseByteSize(%q) error %v, want error %v", test.value, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("parseByteSize(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+// valueTest is a case of runValueTests: executing a command with args sets the attached struct to want, or fails when wantErr.
+type valueTest[T any] struct {
+	name    string
+	args    []string
+	want    T
+	wantErr bool
+}
+
+// runValueTests attaches a new T to a command for each test, executes it with the test args and compares the struct, and the struct Unmarshal reads back from the flags, with want.
+func runValueTests[T any](t *testing.T, tests []valueTest[T]) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got T
+			cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+			cmd.SilenceErrors, cmd.SilenceUsage = true, true
+			if err := AttachStructArgs(cmd, &got); err != nil {
+				t.Fatal(err)
+			}
+			cmd.SetArgs(test.args)
+			if err := cmd.Execute(); (err != nil) != test.wantErr {
+				t.Fatalf("Execute error %v, want error %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			var unmarshaled T
+			if err := Unmarshal(cmd, &unmarshaled); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(unmarshaled, test.want) {
+				t.Errorf("Unmarshal got %+v, want %+v", unmarshaled, test.want)
+			}
+		})
+	}
+}
+
+func TestFloat64Args(t *testing.T) {
+	type floatArgs struct {
+		Ratio float64 `arg:"shortname=r,defaultvalue=0.25"`
+		Scale float64
+	}
+	runValueTests(t, []valueTest[floatArgs]{
+		{name: "defaults", want: floatArgs{Ratio: 0.25}},
+		{name: "flags", args: []string{"-r", "1.5", "--scale", "-2e3"}, want: floatArgs{Ratio: 1.5, Scale: -2000}},
+		{name: "not a number", args: []string{"--scale", "big"}, wantErr: true},
+	})
+}
+
+func TestDurationArgs(t *testing.T) {
+	type durationArgs struct {
+		Timeout time.Duration `arg:"defaultvalue=1m30s"`
+		Delay   time.Duration
+	}
+	runValueTests(t, []valueTest[durationArgs]{
+		{name: "defaults", want: durationArgs{Timeout: 90 * time.Second}},
+		{name: "flags", args: []string{"--timeout", "2h", "--delay", "250ms"}, want: durationArgs{Timeout: 2 * time.Hour, Delay: 250 * time.Millisecond}},
+		{name: "no unit", args: []string{"--delay", "5"}, wantErr: true},
+	})
+}
+
+func TestStringSliceArgs(t *testing.T) {
+	type sliceArgs struct {
+		Hosts []string `arg:"mode=slice,defaultvalue=a:b"`
+		Tags  []string `arg:"mode=slice,sep=;,defaultvalue=x;y"`
+	}
+	runValueTests(t, []valueTest[sliceArgs]{
+		{name: "defaults", want: sliceArgs{Hosts: []string{"a", "b"}, Tags: []string{"x", "y"}}},
+		{name: "comma separated", args: []string{"--hosts", "c,d"}, want: sliceArgs{Hosts: []string{"c", "d"}, Tags: []string{"x", "y"}}},
+		{name: "repeated", args: []string{"--tags", "p", "--tags", "q,r"}, want: sliceArgs{Hosts: []string{"a", "b"}, Tags: []string{"p", "q", "r"}}},
+	})
+}
+
+func TestIntSliceArgs(t *testing.T) {
+	type intSliceArgs struct {
+		Ports []int   `arg:"defaultvalue=80:443"`
+		Ids   []int64 `arg:"sep=|,defaultvalue=9007199254740993"`
+	}
+	runValueTests(t, []valueTest[intSliceArgs]{
+		{name: "defaults", want: intSliceArgs{Ports: []int{80, 443}, Ids: []int64{9007199254740993}}},
+		{name: "flags", args: []string{"--ports", "8080,8443", "--ids", "1", "--ids", "-2"}, want: intSliceArgs{Ports: []int{8080, 8443}, Ids: []int64{1, -2}}},
+		{name: "not an int", args: []string{"--ports", "80,http"}, wantErr: true},
+	})
+	type badDefault struct {
+		Ports []int `arg:"defaultvalue=80:http"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestMapArgs(t *testing.T) {
+	type mapArgs struct {
+		Labels  map[string]string `arg:"defaultvalue=env=dev:team=core"`
+		Weights map[string]int
+	}
+	runValueTests(t, []valueTest[mapArgs]{
+		{name: "defaults", want: mapArgs{Labels: map[string]string{"env": "dev", "team": "core"}, Weights: map[string]int{}}},
+		{name: "flags", args: []string{"--labels", "env=prod,url=a=b", "--weights", "x=1", "--weights", "y=-2"}, want: mapArgs{Labels: map[string]string{"env": "prod", "url": "a=b"}, Weights: map[string]int{"x": 1, "y": -2}}},
+		{name: "not a pair", args: []string{"--weights", "x"}, wantErr: true},
+		{name: "not an int", args: []string{"--weights", "x=big"}, wantErr: true},
+	})
+	type badDefault struct {
+		Labels map[string]string `arg:"defaultvalue=env"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestTimeArgs(t *testing.T) {
+	type timeArgs struct {
+		Since time.Time `arg:"layout=2006-01-02,defaultvalue=2024-01-31"`
+		Until time.Time
+	}
+	runValueTests(t, []valueTest[timeArgs]{
+		{name: "defaults", want: timeArgs{Since: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		{name: "layout", args: []string{"--since", "2024-02-29"}, want: timeArgs{Since: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)}},
+		{name: "RFC 3339 without layout", args: []string{"--until", "2024-02-03T04:05:06Z"}, want: timeArgs{Since: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)}},
+		{name: "layout mismatch", args: []string{"--since", "02/03/2024"}, wantErr: true},
+		{name: "invalid date", args: []string{"--since", "2023-02-29"}, wantErr: true},
+	})
+	type badDefault struct {
+		Since time.Time `arg:"layout=2006-01-02,defaultvalue=yesterday-ish"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestTimeKeywords(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tests := []struct {
+		keyword string
+		want    time.Time
+	}{
+		{keyword: "today", want: today},
+		{keyword: "yesterday", want: today.AddDate(0, 0, -1)},
+		{keyword: "tomorrow", want: today.AddDate(0, 0, 1)},
+	}
+	for _, test := range tests {
+		t.Run(test.keyword, func(t *testing.T) {
+			var got time.Time
+			if err := newTimeValue(&got, "2006-01-02").Set(test.keyword); err != nil {
+				t.Fatal(err)
+			}
+			// a run straddling midnight sees the next day
+			if !got.Equal(test.want) && !got.Equal(test.want.AddDate(0, 0, 1)) {
+				t.Errorf("%v is %v, want %v", test.keyword, got, test.want)
+			}
+		})
+	}
+	var got time.Time
+	if err := newTimeValue(&got, time.RFC3339).Set("now"); err != nil {
+		t.Fatal(err)
+	}
+	if got.Before(now) || got.Sub(now) > time.Minute {
+		t.Errorf("now is %v, want about %v", got, now)
+	}
+}
+
+func TestIPArgs(t *testing.T) {
+	type ipArgs struct {
+		Bind  net.IP    `arg:"defaultvalue=127.0.0.1"`
+		Allow net.IPNet `arg:"defaultvalue=10.0.0.0/8"`
+		Peer  net.IP
+		Deny  net.IPNet
+	}
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	_, documentation, _ := net.ParseCIDR("2001:db8::/32")
+	runValueTests(t, []valueTest[ipArgs]{
+		{name: "defaults", want: ipArgs{Bind: net.ParseIP("127.0.0.1"), Allow: *private}},
+		{name: "flags", args: []string{"--bind", "::1", "--deny", "2001:db8::/32", "--peer", "192.0.2.1"}, want: ipArgs{Bind: net.ParseIP("::1"), Allow: *private, Peer: net.ParseIP("192.0.2.1"), Deny: *documentation}},
+		{name: "not an IP", args: []string{"--bind", "localhost"}, wantErr: true},
+		{name: "not a CIDR", args: []string{"--deny", "10.0.0.1"}, wantErr: true},
+	})
+}
+
+func TestURLArgs(t *testing.T) {
+	type urlArgs struct {
+		Endpoint *url.URL `arg:"schemes=https|http,defaultvalue=https://example.com/api"`
+		Proxy    *url.URL
+	}
+	mustParse := func(rawURL string) *url.URL {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	runValueTests(t, []valueTest[urlArgs]{
+		{name: "defaults", want: urlArgs{Endpoint: mustParse("https://example.com/api")}},
+		{name: "flags", args: []string{"--endpoint", "HTTP://localhost:8080", "--proxy", "socks5://proxy:1080"}, want: urlArgs{Endpoint: mustParse("HTTP://localhost:8080"), Proxy: mustParse("socks5://proxy:1080")}},
+		{name: "scheme rejected", args: []string{"--endpoint", "ftp://example.com"}, wantErr: true},
+		{name: "no scheme rejected", args: []string{"--endpoint", "example.com"}, wantErr: true},
+		{name: "unparsable", args: []string{"--proxy", "http://[::1"}, wantErr: true},
+	})
+	type badDefault struct {
+		Endpoint *url.URL `arg:"schemes=https,defaultvalue=http://example.com"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestCountArgs(t *testing.T) {
+	type countArgs struct {
+		Verbose int `arg:"shortname=v,type=count"`
+	}
+	runValueTests(t, []valueTest[countArgs]{
+		{name: "unset", want: countArgs{}},
+		{name: "once", args: []string{"-v"}, want: countArgs{Verbose: 1}},
+		{name: "stacked", args: []string{"-vvv"}, want: countArgs{Verbose: 3}},
+		{name: "long and short", args: []string{"--verbose", "-vv"}, want: countArgs{Verbose: 3}},
+		{name: "explicit", args: []string{"--verbose=5"}, want: countArgs{Verbose: 5}},
+		{name: "not a number", args: []string{"--verbose=lots"}, wantErr: true},
+	})
+}
+
+func TestUintArgs(t *testing.T) {
+	type uintArgs struct {
+		Workers uint   `arg:"defaultvalue=4"`
+		Level   uint8  `arg:"defaultvalue=255"`
+		Port    uint16 `arg:"defaultvalue=8080"`
+		Flags   uint32
+		Size    uint64
+	}
+	defaults := uintArgs{Workers: 4, Level: 255, Port: 8080}
+	runValueTests(t, []valueTest[uintArgs]{
+		{name: "defaults", want: defaults},
+		{name: "maximums", args: []string{"--port", "65535", "--flags", "4294967295", "--size", "18446744073709551615"}, want: uintArgs{Workers: 4, Level: 255, Port: 65535, Flags: 4294967295, Size: 18446744073709551615}},
+		{name: "uint8 overflow", args: []string{"--level", "256"}, wantErr: true},
+		{name: "uint16 overflow", args: []string{"--port", "65536"}, wantErr: true},
+		{name: "negative", args: []string{"--workers", "-1"}, wantErr: true},
+	})
+	type badDefault struct {
+		Level uint8 `arg:"defaultvalue=300"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestIntWidthArgs(t *testing.T) {
+	type intArgs struct {
+		Small  int8  `arg:"defaultvalue=-128"`
+		Medium int16 `arg:"defaultvalue=32767"`
+		Large  int32
+		Huge   int64 `arg:"defaultvalue=-9223372036854775808"`
+	}
+	runValueTests(t, []valueTest[intArgs]{
+		{name: "defaults", want: intArgs{Small: -128, Medium: 32767, Huge: -9223372036854775808}},
+		{name: "flags", args: []string{"--small", "127", "--large", "-2147483648", "--huge", "9223372036854775807"}, want: intArgs{Small: 127, Medium: 32767, Large: -2147483648, Huge: 9223372036854775807}},
+		{name: "int8 overflow", args: []string{"--small", "128"}, wantErr: true},
+		{name: "int16 overflow", args: []string{"--medium", "32768"}, wantErr: true},
+		{name: "int32 overflow", args: []string{"--large", "2147483648"}, wantErr: true},
+		{name: "int64 overflow", args: []string{"--huge", "9223372036854775808"}, wantErr: true},
+	})
+	type badDefault struct {
+		Small int8 `arg:"defaultvalue=200"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestBytesArgs(t *testing.T) {
+	type bytesArgs struct {
+		Key  []byte `arg:"defaultvalue=c0ffee"`
+		Cert []byte `arg:"encoding=base64,defaultvalue=aGk="`
+	}
+	runValueTests(t, []valueTest[bytesArgs]{
+		{name: "defaults", want: bytesArgs{Key: []byte{0xc0, 0xff, 0xee}, Cert: []byte("hi")}},
+		{name: "flags", args: []string{"--key", "DEADBEEF", "--cert", "aGVsbG8="}, want: bytesArgs{Key: []byte{0xde, 0xad, 0xbe, 0xef}, Cert: []byte("hello")}},
+		{name: "odd hex", args: []string{"--key", "abc"}, wantErr: true},
+		{name: "not hex", args: []string{"--key", "zz"}, wantErr: true},
+		{name: "not base64", args: []string{"--cert", "a*b"}, wantErr: true},
+	})
+	type badEncoding struct {
+		Key []byte `arg:"encoding=base32"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badEncoding{}); !errors.Is(err, ErrInvalidTagValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrInvalidTagValue)
+	}
+}
+
+// upperValue is a pflag.Value holding its value upper cased.
+type upperValue struct {
+	text string
+}
+
+func (value *upperValue) Set(text string) error {
+	if text == "" {
+		return errors.New("empty value")
+	}
+	value.text = strings.ToUpper(text)
+	return nil
+}
+
+func (value *upperValue) String() string {
+	return value.text
+}
+
+func (value *upperValue) Type() string {
+	return "upper"
+}
+
+func TestPflagValueArgs(t *testing.T) {
+	type pflagValueArgs struct {
+		Name  upperValue `arg:"defaultvalue=bob"`
+		Alias *upperValue
+	}
+	runValueTests(t, []valueTest[pflagValueArgs]{
+		{name: "defaults", want: pflagValueArgs{Name: upperValue{text: "BOB"}, Alias: &upperValue{}}},
+		{name: "flags", args: []string{"--name", "alice", "--alias", "al"}, want: pflagValueArgs{Name: upperValue{text: "ALICE"}, Alias: &upperValue{text: "AL"}}},
+		{name: "rejected", args: []string{"--name="}, wantErr: true},
+	})
+}
+
+// rgbColor is an encoding.TextUnmarshaler parsing #rrggbb colors.
+type rgbColor struct {
+	R, G, B uint8
+}
+
+func (color *rgbColor) UnmarshalText(text []byte) error {
+	if _, err := fmt.Sscanf(string(text), "#%02x%02x%02x", &color.R, &color.G, &color.B); err != nil || len(text) != 7 {
+		return fmt.Errorf("color [%s] is not of the form #rrggbb", text)
+	}
+	return nil
+}
+
+func (color rgbColor) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)), nil
+}
+
+func TestTextUnmarshalerArgs(t *testing.T) {
+	type colorArgs struct {
+		Fill   rgbColor `arg:"defaultvalue=#ff8000"`
+		Stroke *rgbColor
+	}
+	runValueTests(t, []valueTest[colorArgs]{
+		{name: "defaults", want: colorArgs{Fill: rgbColor{R: 0xff, G: 0x80}, Stroke: &rgbColor{}}},
+		{name: "flags", args: []string{"--fill", "#000001", "--stroke", "#abcdef"}, want: colorArgs{Fill: rgbColor{B: 1}, Stroke: &rgbColor{R: 0xab, G: 0xcd, B: 0xef}}},
+		{name: "rejected", args: []string{"--fill", "red"}, wantErr: true},
+	})
+	type badDefault struct {
+		Fill rgbColor `arg:"defaultvalue=red"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestOneOfArgs(t *testing.T) {
+	type oneOfArgs struct {
+		Level string `arg:"oneof=debug|info|warn,defaultvalue=info"`
+		Size  int    `arg:"oneof=1|2|4,defaultvalue=1"`
+	}
+	runValueTests(t, []valueTest[oneOfArgs]{
+		{name: "defaults", want: oneOfArgs{Level: "info", Size: 1}},
+		{name: "flags", args: []string{"--level", "warn", "--size", "4"}, want: oneOfArgs{Level: "warn", Size: 4}},
+		{name: "string rejected", args: []string{"--level", "trace"}, wantErr: true},
+		{name: "case sensitive", args: []string{"--level", "INFO"}, wantErr: true},
+		{name: "int rejected", args: []string{"--size", "3"}, wantErr: true},
+	})
+	type badDefault struct {
+		Level string `arg:"oneof=debug|info,defaultvalue=trace"`
+	}
+	if err := AttachStructArgs(&cobra.Command{Use: "app"}, &badDefault{}); !errors.Is(err, ErrBadDefaultValue) {
+		t.Errorf("AttachStructArgs error %v, want %v", err, ErrBadDefaultValue)
+	}
+}
+
+func TestOneOfCompletion(t *testing.T) {
+	type levelArgs struct {
+		Level string `arg:"oneof=debug|info,choicehelp=noisy|normal"`
+	}
+	var out bytes.Buffer
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	if err := AttachStructArgs(cmd, &levelArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{cobra.ShellCompRequestCmd, "--level", ""})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "debug\tnoisy\ninfo\tnormal\n:4\n"; !strings.HasPrefix(out.String(), want) {
+		t.Errorf("completion output %q, want it to start with %q", out.String(), want)
+	}
+}
+
+func TestJSONArgs(t *testing.T) {
+	type selector struct {
+		App  string `json:"app"`
+		Tier int    `json:"tier"`
+	}
+	type jsonArgs struct {
+		Raw      json.RawMessage
+		Selector selector `arg:"format=json"`
+	}
+	runValueTests(t, []valueTest[jsonArgs]{
+		{name: "defaults", want: jsonArgs{}},
+		{name: "flags", args: []string{"--raw", `{"a":[1,2]}`, "--selector", `{"app":"web","tier":2}`}, want: jsonArgs{Raw: json.RawMessage(`{"a":[1,2]}`), Selector: selector{App: "web", Tier: 2}}},
+		{name: "invalid raw", args: []string{"--raw", `{"a":`}, wantErr: true},
+		{name: "wrong type", args: []string{"--selector", `{"tier":"two"}`}, wantErr: true},
+	})
+}
+
+func TestFileModeArgs(t *testing.T) {
+	type modeArgs struct {
+		Mode  os.FileMode `arg:"defaultvalue=0640"`
+		Umask os.FileMode
+	}
+	runValueTests(t, []valueTest[modeArgs]{
+		{name: "defaults", want: modeArgs{Mode: 0640}},
+		{name: "flags", args: []string{"--mode", "755", "--umask", "0022"}, want: modeArgs{Mode: 0755, Umask: 0022}},
+		{name: "not octal", args: []string{"--mode", "0999"}, wantErr: true},
+		{name: "not a number", args: []string{"--mode", "rwx"}, wantErr: true},
+	})
+	cmd := &cobra.Command{Use: "app"}
+	if err := AttachStructArgs(cmd, &modeArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	if usage := cmd.Flags().FlagUsages(); !strings.Contains(usage, `(default 0640)`) {
+		t.Errorf("usage %q, want the default in octal", usage)
+	}
+}
+
+func TestDurationIPSliceArgs(t *testing.T) {
+	type collectionArgs struct {
+		Backoff []time.Duration `arg:"defaultvalue=1s:5s"`
+		Peers   []net.IP
+	}
+	runValueTests(t, []valueTest[collectionArgs]{
+		{name: "defaults", want: collectionArgs{Backoff: []time.Duration{time.Second, 5 * time.Second}, Peers: []net.IP{}}},
+		{name: "flags", args: []string{"--backoff", "100ms,2m", "--peers", "10.0.0.1", "--peers", "::1"}, want: collectionArgs{Backoff: []time.Duration{100 * time.Millisecond, 2 * time.Minute}, Peers: []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}}},
+		{name: "bad duration", args: []string{"--backoff", "1s,soon"}, wantErr: true},
+		{name: "bad ip", args: []string{"--peers", "10.0.0.256"}, wantErr: true},
+	})
+}
+
+func TestFloatSliceArgs(t *testing.T) {
+	type tuningArgs struct {
+		Weights []float32 `arg:"defaultvalue=0.5:0.25"`
+		Rates   []float64
+		Decay   float32 `arg:"defaultvalue=0.9"`
+	}
+	runValueTests(t, []valueTest[tuningArgs]{
+		{name: "defaults", want: tuningArgs{Weights: []float32{0.5, 0.25}, Rates: []float64{}, Decay: 0.9}},
+		{name: "flags", args: []string{"--weights", "1,2.5", "--rates", "1e-3", "--rates", "0.1", "--decay", "0.99"}, want: tuningArgs{Weights: []float32{1, 2.5}, Rates: []float64{1e-3, 0.1}, Decay: 0.99}},
+		{name: "bad item", args: []string{"--rates", "0.1,fast"}, wantErr: true},
+		{name: "float32 overflow", args: []string{"--decay", "1e39"}, wantErr: true},
+	})
+	var decay float32
+	cmd := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	AttachFloat32Arg(cmd, reflect.TypeOf(tuningArgs{}), "Decay", &decay)
+	cmd.SetArgs([]string{"--decay", "0.5"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if decay != 0.5 {
+		t.Errorf("AttachFloat32Arg got %v, want 0.5", decay)
+	}
+}
+
+func TestStringArrayArgs(t *testing.T) {
+	type arrayArgs struct {
+		Exprs   []string `arg:"defaultvalue=a:b"`
+		Filters []string `arg:"mode=array,defaultvalue=all"`
+		Hosts   []string `arg:"mode=slice,defaultvalue=local"`
+	}
+	runValueTests(t, []valueTest[arrayArgs]{
+		{name: "defaults", want: arrayArgs{Exprs: []string{"a", "b"}, Filters: []string{"all"}, Hosts: []string{"local"}}},
+		{name: "commas kept", args: []string{"--exprs", "x,y", "--exprs", "z", "--filters", "name in (a,b)"}, want: arrayArgs{Exprs: []string{"x,y", "z"}, Filters: []string{"name in (a,b)"}, Hosts: []string{"local"}}},
+		{name: "commas split", args: []string{"--hosts", "a,b", "--hosts", "c"}, want: arrayArgs{Exprs: []string{"a", "b"}, Filters: []string{"all"}, Hosts: []string{"a", "b", "c"}}},
+	})
+}
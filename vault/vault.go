@@ -0,0 +1,96 @@
+// Package vault resolves HashiCorp Vault references for cobraargs. Register it for fields tagged 'indirect=vault':
+//
+//	cobraargs.RegisterResolver("vault", vault.NewFromEnv())
+//
+// after which a flag value of vault:secret/data/app#api_key is replaced by the api_key field of the secret at secret/data/app.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Resolver reads secrets through the Vault HTTP API. KV version 2 paths (secret/data/...) and version 1 paths are both supported.
+type Resolver struct {
+	// Address is the Vault server URL, e.g. https://vault.example.com:8200.
+	Address string
+	// Token authenticates each request.
+	Token string
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// NewFromEnv returns a Resolver configured from the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables, as the vault CLI is.
+func NewFromEnv() *Resolver {
+	return &Resolver{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+// Resolve returns the field of the secret named by reference, written path#field.
+func (resolver *Resolver) Resolve(ctx context.Context, reference string) (string, error) {
+	path, field, ok := strings.Cut(reference, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault reference [%v] is not of the form path#field", reference)
+	}
+	if resolver.Address == "" {
+		return "", fmt.Errorf("vault address is not set")
+	}
+	if resolver.Token == "" {
+		return "", fmt.Errorf("vault token is not set")
+	}
+	endpoint, err := url.JoinPath(resolver.Address, "v1", path)
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("X-Vault-Token", resolver.Token)
+	if resolver.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", resolver.Namespace)
+	}
+	client := resolver.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault read of %v failed: %v", path, response.Status)
+	}
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("vault read of %v: %v", path, err)
+	}
+	values := secret.Data
+	if nested, ok := values["data"].(map[string]interface{}); ok {
+		if _, isMetadata := values["metadata"]; isMetadata {
+			values = nested
+		}
+	}
+	value, has := values[field]
+	if !has {
+		return "", fmt.Errorf("vault secret %v has no field [%v]", path, field)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
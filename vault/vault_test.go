@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("X-Vault-Token") != "s.token" {
+			http.Error(writer, "permission denied", http.StatusForbidden)
+			return
+		}
+		if request.Header.Get("X-Vault-Namespace") != "team" {
+			http.Error(writer, "no namespace", http.StatusBadRequest)
+			return
+		}
+		switch request.URL.Path {
+		case "/v1/secret/data/app":
+			writer.Write([]byte(`{"data":{"data":{"api_key":"k2","port":8080},"metadata":{"version":3}}}`))
+		case "/v1/kv/app":
+			writer.Write([]byte(`{"data":{"api_key":"k1"}}`))
+		case "/v1/secret/data/broken":
+			writer.Write([]byte(`{"data":`))
+		default:
+			http.NotFound(writer, request)
+		}
+	}))
+	defer server.Close()
+	tests := []struct {
+		name      string
+		configure func(*Resolver)
+		reference string
+		want      string
+		wantErr   string
+	}{
+		{name: "kv version 2", reference: "secret/data/app#api_key", want: "k2"},
+		{name: "kv version 1", reference: "kv/app#api_key", want: "k1"},
+		{name: "non-string field", reference: "secret/data/app#port", want: "8080"},
+		{name: "missing field", reference: "secret/data/app#password", wantErr: "has no field [password]"},
+		{name: "not found", reference: "secret/data/missing#api_key", wantErr: "404 Not Found"},
+		{name: "bad response", reference: "secret/data/broken#api_key", wantErr: "vault read of secret/data/broken"},
+		{name: "bad reference", reference: "secret/data/app", wantErr: "not of the form path#field"},
+		{name: "wrong token", configure: func(resolver *Resolver) { resolver.Token = "s.other" }, reference: "secret/data/app#api_key", wantErr: "403 Forbidden"},
+		{name: "missing token", configure: func(resolver *Resolver) { resolver.Token = "" }, reference: "secret/data/app#api_key", wantErr: "vault token is not set"},
+		{name: "missing address", configure: func(resolver *Resolver) { resolver.Address = "" }, reference: "secret/data/app#api_key", wantErr: "vault address is not set"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolver := Resolver{Address: server.URL, Token: "s.token", Namespace: "team", Client: server.Client()}
+			if test.configure != nil {
+				test.configure(&resolver)
+			}
+			got, err := resolver.Resolve(context.Background(), test.reference)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Resolve(%q) error %v, want one containing %q", test.reference, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("Resolve(%q) = %q, want %q", test.reference, got, test.want)
+			}
+		})
+	}
+}
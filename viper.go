@@ -0,0 +1,27 @@
+package cobraargs
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// attachedAnnotation marks a flag as attached by this package, as opposed to flags added by hand or by cobra itself.
+const attachedAnnotation = "cobraargs_attached"
+
+// FlagBinder is the part of *viper.Viper that BindViper needs, so this package does not depend on viper.
+type FlagBinder interface {
+	BindPFlag(key string, flag *pflag.Flag) error
+}
+
+// BindViper binds every flag attached to cmd by this package into binder, normally a *viper.Viper, under its long name. Viper then resolves each key with flag > env > config file > default precedence without a BindPFlag call per flag.
+func BindViper(binder FlagBinder, cmd *cobra.Command) error {
+	var err error
+	bind := func(flag *pflag.Flag) {
+		if err == nil && flag.Annotations[attachedAnnotation] != nil {
+			err = binder.BindPFlag(flag.Name, flag)
+		}
+	}
+	cmd.Flags().VisitAll(bind)
+	cmd.PersistentFlags().VisitAll(bind)
+	return err
+}